@@ -0,0 +1,44 @@
+package main
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/database"
+	"PromptGallery/internal/repositories"
+	"PromptGallery/internal/services"
+	"time"
+
+	"log"
+)
+
+// Hard-deletes prompts that have been soft-deleted for longer than PRUNE_RETENTION_DAYS
+// Meant to be run manually or from cron
+func main() {
+
+	cfg := config.LoadConfig()
+
+	err := database.ConnectDatabase(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database", err)
+	}
+
+	defer database.CloseDatabase()
+
+	// No worker pool: a nil *worker.Pool runs submitted tasks inline, and a one-off
+	// command has no shutdown to drain a queue for. Purging itself submits nothing
+	db := database.GetDb()
+	promptService := services.NewPromptService(
+		repositories.NewPromptRepository(db, cfg.TitleCollation, cfg.QualityWeights),
+		repositories.NewUserRepository(db),
+		cfg,
+		nil,
+	)
+
+	retention := time.Duration(cfg.PruneRetentionDays) * 24 * time.Hour
+
+	purged, err := promptService.PurgeDeletedPrompts(retention)
+	if err != nil {
+		log.Fatalf("❌ Prune failed: %v", err)
+	}
+
+	log.Printf("🧹 Pruned %d prompts deleted more than %d days ago", purged, cfg.PruneRetentionDays)
+}
@@ -3,65 +3,162 @@ package main
 import (
 	"PromptGallery/internal/config"
 	"PromptGallery/internal/database"
+	"PromptGallery/internal/github"
 	"PromptGallery/internal/handlers"
+	"PromptGallery/internal/middleware"
 	"PromptGallery/internal/repositories"
 	"PromptGallery/internal/services"
+	"PromptGallery/internal/worker"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
 
 func main() {
 
 	cfg := config.LoadConfig()
 
-	err := database.ConnectDatabase(cfg.DatabaseURL, cfg.Environment)
+	err := database.ConnectDatabase(cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database", err)
 	}
 
 	defer database.CloseDatabase()
 
-	app := fiber.New(fiber.Config{
+	tasks := worker.NewPool(cfg.WorkerCount, cfg.WorkerQueueSize)
+	defer tasks.Stop()
+
+	appConfig := fiber.Config{
 		AppName: "PromptGallery API v1.0",
-	})
+	}
+
+	// Behind a proxy c.IP() (access log, rate limits) should be the client, not the proxy
+	// The header is only read on requests coming from a trusted proxy
+	if len(cfg.TrustedProxies) > 0 {
+		appConfig.EnableTrustedProxyCheck = true
+		appConfig.TrustedProxies = cfg.TrustedProxies
+		appConfig.ProxyHeader = cfg.ProxyHeader
+		appConfig.EnableIPValidation = true // X-Forwarded-For may be a list, use its first valid IP
+	}
+
+	app := fiber.New(appConfig)
+
+	setUpMiddlewares(app, cfg)
 
-	setUpMiddlewares(app)
+	// Closed on shutdown to stop background tickers
+	stop := make(chan struct{})
+	defer close(stop)
 
-	setupDependencies(app)
+	setupDependencies(app, cfg, tasks, stop)
 
-	log.Println("Server running on port %s ", cfg.Port)
-	log.Fatal(app.Listen(":" + cfg.Port))
+	go func() {
+		log.Printf("Server running on port %s ", cfg.Port)
+		if err := app.Listen(":" + cfg.Port); err != nil {
+			log.Printf("❌ Server stopped: %v", err)
+		}
+	}()
+
+	// Wait for Ctrl+C / SIGTERM, then stop accepting requests before the deferred
+	// worker drain and database close run
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Println("🛑 Shutting down...")
+	if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+		log.Printf("❌ Server shutdown failed: %v", err)
+	}
 
 }
 
-func setUpMiddlewares(app *fiber.App) {
+func setUpMiddlewares(app *fiber.App, cfg *config.Config) {
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,PATCH",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowOrigins:     strings.Join(cfg.CORSAllowOrigins, ","),
+		AllowMethods:     "GET,POST,PUT,DELETE,PATCH",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
 	}))
 
-	app.Use(logger.New(logger.Config{
-		Format: "[${ip}] ${status} - ${method} ${path}\n",
-	}))
+	app.Use(requestid.New())
+
+	if cfg.LogFormat == "json" {
+		app.Use(middleware.JSONLogger(os.Stdout))
+	} else {
+		app.Use(logger.New(logger.Config{
+			Format: "[${ip}] ${status} - ${method} ${path}\n",
+		}))
+	}
+
+	routeBudgets := make(map[string]time.Duration, len(cfg.RouteBudgets))
+	for route, ms := range cfg.RouteBudgets {
+		routeBudgets[route] = time.Duration(ms) * time.Millisecond
+	}
+	app.Use(middleware.RouteBudget(time.Duration(cfg.RouteBudgetMs)*time.Millisecond, routeBudgets))
+
+	app.Use(middleware.SearchRateLimit(cfg.SearchRateLimit, time.Duration(cfg.SearchRateWindowSeconds)*time.Second))
+
+	app.Use(middleware.StringIDs(cfg.StringIDs))
 }
 
-func setupDependencies(app *fiber.App) {
+func setupDependencies(app *fiber.App, cfg *config.Config, tasks *worker.Pool, stop <-chan struct{}) {
 	db := database.GetDb()
 
-	promptRepo := repositories.NewPromptRepository(db)
+	promptRepo := repositories.NewPromptRepository(db, cfg.TitleCollation, cfg.QualityWeights)
+	userRepo := repositories.NewUserRepository(db)
+	viewRepo := repositories.NewSavedViewRepository(db)
+	requestRepo := repositories.NewPromptRequestRepository(db)
 
-	promptService := services.NewPromptService(promptRepo)
+	promptService := services.NewPromptService(promptRepo, userRepo, cfg, tasks)
+	userService := services.NewUserService(userRepo, promptRepo, requestRepo, cfg)
+	viewService := services.NewSavedViewService(viewRepo)
+	requestService := services.NewPromptRequestService(requestRepo)
+	importService := services.NewImportService(promptService, github.NewClient(cfg.GitHubAPIURL, cfg.GitHubToken), cfg)
 
-	promptHandler := handlers.NewPromptHandler(promptService)
+	promptHandler := handlers.NewPromptHandler(promptService, cfg)
+	userHandler := handlers.NewUserHandler(userService, cfg)
+	viewHandler := handlers.NewSavedViewHandler(viewService, promptService, cfg)
+	requestHandler := handlers.NewPromptRequestHandler(requestService, cfg)
+	importHandler := handlers.NewImportHandler(importService, cfg)
+
+	// Before the routes so every handler sees the caller through the "user" local
+	app.Use(middleware.Authenticate(userService.UserForToken))
+
+	if cfg.CountSnapshotIntervalMinutes > 0 {
+		go runCountSnapshots(promptService, time.Duration(cfg.CountSnapshotIntervalMinutes)*time.Minute,
+			time.Duration(cfg.CountSnapshotRetentionDays)*24*time.Hour, stop)
+	}
 
-	setupRoutes(app, promptHandler)
+	setupRoutes(app, cfg, promptHandler, userHandler, viewHandler, requestHandler, importHandler)
 }
 
-func setupRoutes(app *fiber.App, promptHandler *handlers.PromptHandler) {
+// runCountSnapshots snapshots the prompt counters every interval until stop is closed
+// The snapshots are the baselines GET /prompts/count-deltas compares against
+func runCountSnapshots(promptService *services.PromptService, interval, retention time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := promptService.SnapshotCounts(retention); err != nil {
+				log.Printf("❌ Count snapshot failed: %v", err)
+			}
+		}
+	}
+}
+
+func setupRoutes(app *fiber.App, cfg *config.Config, promptHandler *handlers.PromptHandler, userHandler *handlers.UserHandler, viewHandler *handlers.SavedViewHandler, requestHandler *handlers.PromptRequestHandler, importHandler *handlers.ImportHandler) {
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status":  "ok",
@@ -69,27 +166,123 @@ func setupRoutes(app *fiber.App, promptHandler *handlers.PromptHandler) {
 		})
 	})
 
+	// Sitemap for crawlers, served at the root rather than under /api/v1
+	app.Get("/sitemap.xml", promptHandler.GetSitemap)
+	app.Get("/sitemap-:page.xml", promptHandler.GetSitemapPage)
+
 	api := app.Group("/api/v1")
 
+	// Shared slots for heavy endpoints so a burst can't exhaust the DB pool
+	expensive := middleware.ConcurrencyLimit(cfg.ExpensiveOpsConcurrency)
+
 	// Prompt routes
-	setupPromptRoutes(api, promptHandler)
+	setupPromptRoutes(api, promptHandler, importHandler, expensive)
+
+	// Auth routes
+	api.Post("/auth/tokens", userHandler.CreateToken)
+	api.Delete("/auth/tokens/current", userHandler.RevokeToken)
+
+	// User routes
+	setupUserRoutes(api, userHandler, expensive)
+
+	// Prompt request routes
+	setupRequestRoutes(api, requestHandler)
+
+	// Stats routes
+	api.Get("/stats/languages", promptHandler.GetLanguageStats)
+
+	// Tag routes
+	api.Get("/tags/trending", promptHandler.GetTrendingTags)
+
+	// Meta routes
+	api.Get("/meta/enums", handlers.GetEnums)
+
+	// Current user routes
+	setupMeRoutes(api, promptHandler, userHandler, viewHandler)
 
 	// 404 handler (catch-all)
-	app.Use("*", func(c *fiber.Ctx) error {
-		return c.Status(404).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Route not found",
-		})
-	})
+	app.Use("*", handlers.RouteNotFound)
 }
 
-func setupPromptRoutes(router fiber.Router, handler *handlers.PromptHandler) {
+func setupPromptRoutes(router fiber.Router, handler *handlers.PromptHandler, importHandler *handlers.ImportHandler, expensive fiber.Handler) {
 	prompts := router.Group("/prompts")
 
 	// CRUD routes
 	prompts.Get("/", handler.GetPrompts)
 	prompts.Post("/", handler.CreatePrompt)
+	prompts.Post("/validate", handler.ValidatePrompt)
+	prompts.Get("/count", handler.CountPrompts) // before /:id so "count" isn't taken as an id
+	prompts.Get("/study-mix", handler.GetStudyMix)
+	prompts.Get("/daily", handler.GetDailyPrompt)
+	prompts.Get("/count-deltas", handler.GetCountDeltas)
+	prompts.Put("/external/:external_id", handler.UpsertByExternalID)
+	prompts.Get("/pending-verification", handler.GetPendingVerification)
+	prompts.Get("/:id.md", handler.GetPromptMarkdown) // before /:id, which would otherwise get "5.md"
 	prompts.Get("/:id", handler.GetPromptByID)
+	prompts.Put("/:id", handler.UpdatePrompt)
 	prompts.Delete("/:id", handler.DeletePrompt)
 
+	// Engagement
+	prompts.Post("/:id/like", handler.LikePrompt)
+	prompts.Post("/:id/like/toggle", handler.ToggleLike)
+
+	// Translations
+	prompts.Get("/:id/translations", handler.GetTranslations)
+	prompts.Put("/:id/translations/:locale", handler.UpsertTranslation)
+
+	// Moderation
+	prompts.Post("/:id/verify", handler.VerifyPrompt)
+	prompts.Post("/:id/unverify", handler.UnverifyPrompt)
+	prompts.Post("/merge", handler.MergePrompts)
+	prompts.Post("/bulk-tag", handler.BulkTag)
+
+	// Import
+	prompts.Post("/import/github", expensive, importHandler.ImportFromGitHub)
+
+}
+
+func setupRequestRoutes(router fiber.Router, handler *handlers.PromptRequestHandler) {
+	requests := router.Group("/requests")
+
+	requests.Post("/", handler.CreateRequest)
+	requests.Get("/completed", handler.GetCompletedRequests)
+	requests.Post("/bulk-priority", handler.BulkPriority)
+	requests.Post("/:id/duplicate-of", handler.MarkDuplicate)
+
+	// Requests a prompt fulfilled, served from the request side of the prompt/request link
+	router.Get("/prompts/:id/source-requests", handler.GetSourceRequests)
+
+	// Admin panel
+	admin := router.Group("/admin")
+	admin.Get("/requests", handler.GetRequests)
+	admin.Patch("/requests/:id", handler.UpdateRequest)
+}
+
+func setupMeRoutes(router fiber.Router, promptHandler *handlers.PromptHandler, userHandler *handlers.UserHandler, viewHandler *handlers.SavedViewHandler) {
+	me := router.Group("/me")
+
+	me.Get("/prompts", promptHandler.GetMyPrompts)
+	me.Get("/summary", userHandler.GetMySummary)
+
+	// Saved views
+	me.Get("/views", viewHandler.GetViews)
+	me.Post("/views", viewHandler.CreateView)
+	me.Get("/views/:id", viewHandler.GetView)
+	me.Put("/views/:id", viewHandler.UpdateView)
+	me.Delete("/views/:id", viewHandler.DeleteView)
+	me.Get("/views/:id/prompts", viewHandler.GetViewPrompts)
+}
+
+func setupUserRoutes(router fiber.Router, handler *handlers.UserHandler, expensive fiber.Handler) {
+	users := router.Group("/users")
+
+	users.Get("/", handler.GetUsers)
+	users.Post("/", handler.CreateUser)
+	users.Get("/by-username/:username", handler.GetUserByUsername)
+	users.Post("/merge", handler.MergeUsers)
+	users.Get("/:id", handler.GetUserByID)
+	users.Patch("/:id", handler.UpdateUser)
+
+	// Admin
+	router.Post("/admin/users/recompute-stats", expensive, handler.RecomputeStats)
 }
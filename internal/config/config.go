@@ -1,16 +1,151 @@
 package config
 
 import (
+	"PromptGallery/internal/models"
+	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	collationPattern  = regexp.MustCompile(`^[A-Za-z0-9_.@-]*$`)
+	identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
 )
 
 type Config struct {
 	Port        string
 	DatabaseURL string
 	Environment string
+
+	// Postgres schema the app's tables live in (sets search_path)
+	DBSchema string
+
+	// Run GORM AutoMigrate on connect, turn off where the schema is managed externally
+	DBAutoMigrate bool
+
+	// Origins allowed by CORS, "*" for any. Credentials (cookies, Authorization from a
+	// browser) need an explicit list, browsers refuse them with a wildcard origin
+	CORSAllowOrigins     []string
+	CORSAllowCredentials bool
+	CORSMaxAge           int // Seconds browsers may cache a preflight response, 0 disables
+
+	// GitHub API used by the Markdown import, the token is optional (public repos, low rate limit without it)
+	GitHubAPIURL string
+	GitHubToken  string
+
+	// Most Markdown files a single import may create prompts from
+	ImportMaxFiles int
+
+	// Proxies/load balancers (IPs or CIDRs) whose ProxyHeader is believed for the client IP
+	// Empty ignores the header, so clients can't spoof their IP past logging and rate limits
+	TrustedProxies []string
+	ProxyHeader    string
+
+	// Searches (requests with ?search=) allowed per IP per window, 0 disables the limit
+	SearchRateLimit         int
+	SearchRateWindowSeconds int
+
+	// Let callers without an account like prompts, one like per IP and prompt per window
+	AnonymousLikes             bool
+	AnonymousLikeWindowSeconds int
+
+	// Bearer tokens issued by POST /auth/tokens stay valid this long
+	APITokenTTLHours int
+
+	// Failed sign-ins allowed per client IP per window before POST /auth/tokens answers 429
+	LoginMaxFailures          int
+	LoginFailureWindowSeconds int
+
+	// bcrypt work factor for password hashes, defaults to bcrypt.MinCost when ENVIRONMENT=test
+	BcryptCost int
+
+	// Email domains new accounts may register with (lowercased), empty allows any. Admins creating users bypass it
+	AllowedEmailDomains []string
+
+	// Public site prompt pages live on, sitemap URLs are SITE_URL/prompts/:id (empty uses this server)
+	SiteURL string
+
+	// Reject request bodies containing fields the target DTO doesn't declare
+	StrictJSONBody bool
+
+	// Serialize ids as JSON strings for every client, not just those asking via Accept
+	StringIDs bool
+
+	// How often view/like counters are snapshotted for count deltas (0 disables) and how long snapshots are kept
+	CountSnapshotIntervalMinutes int
+	CountSnapshotRetentionDays   int
+
+	// Soft-deleted prompts older than this are hard-deleted by cmd/prune
+	PruneRetentionDays int
+
+	// Ordering used by the prompt list when the client doesn't pass ?sort=
+	DefaultPromptSort string
+
+	// Lowest role that may see unverified prompts in the public list/detail endpoints
+	// "anonymous" (everyone) or one of contributor, integrator, moderator, admin, super_admin
+	MinRoleToViewUnverified string
+
+	// max-age (seconds) for anonymous prompt list/detail responses, 0 disables shared caching
+	PromptCacheMaxAge int
+
+	// Languages prompts may be written for (lowercased), empty allows any
+	AllowedLanguages []string
+
+	// Difficulties permitted per category (lowercased), categories not listed allow all
+	// e.g. CATEGORY_DIFFICULTIES=algorithms:beginner|intermediate,web-development:beginner
+	CategoryDifficulties map[string][]string
+
+	// Postgres collation for ?sort=title (e.g. en-US-x-icu), empty sorts by LOWER(title)
+	TitleCollation string
+
+	// Author name shown on prompts submitted without any author details
+	DefaultAuthorName string
+
+	// Postgres statement_timeout in milliseconds, 0 leaves the server default
+	DBStatementTimeoutMs int
+
+	// Cache prepared statements, at most DBPrepareStmtMaxSize distinct queries (least recently
+	// used evicted). Turn off behind a transaction-mode pgbouncer, which can't keep them
+	DBPrepareStmt        bool
+	DBPrepareStmtMaxSize int
+
+	// Upper bounds (in characters) on long prompt fields, 0 disables the check
+	MaxDescriptionLength      int
+	MaxProblemStatementLength int
+
+	// Most tags a prompt may carry after trimming and de-duplicating, 0 disables the check
+	MaxTagsPerPrompt int
+
+	// Requests slower than their route's budget (in milliseconds) are logged as a warning
+	// RouteBudgets overrides the default per "METHOD /route/:template" or "/route/:template"
+	RouteBudgetMs int
+	RouteBudgets  map[string]int
+
+	// Keywords that suggest a prompt is harder than beginner, matched case-insensitively
+	AdvancedKeywords []string
+
+	// How many expensive operations (recomputes, exports, ...) may run at once
+	ExpensiveOpsConcurrency int
+
+	// Access log format: "text" for local dev, "json" for log aggregators
+	LogFormat string
+
+	// Search terms shorter than this are ignored instead of running a full LIKE scan
+	MinSearchLength int
+
+	// Weights for the computed prompt quality_score (and ?sort=quality)
+	QualityWeights models.QualityWeights
+
+	// Background worker pool used for async side effects
+	WorkerCount     int
+	WorkerQueueSize int
 }
 
 func LoadConfig() *Config {
@@ -20,15 +155,119 @@ func LoadConfig() *Config {
 	}
 
 	config := &Config{
-		Port:        getEnv("PORT", "8080"),
-		DatabaseURL: getEnv("DATABASE_URL", ""),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:           getEnv("PORT", "8080"),
+		DatabaseURL:    getEnv("DATABASE_URL", ""),
+		Environment:    getEnv("ENVIRONMENT", "development"),
+		DBSchema:       getEnv("DB_SCHEMA", "public"),
+		StrictJSONBody: getEnvBool("STRICT_JSON_BODY", false),
+		StringIDs:      getEnvBool("STRING_IDS", false),
+		LogFormat:      getEnv("LOG_FORMAT", "text"),
+		DBAutoMigrate:  getEnvBool("DB_AUTO_MIGRATE", true),
+
+		SiteURL: strings.TrimSuffix(getEnv("SITE_URL", ""), "/"),
+
+		CORSAllowOrigins:     getEnvRawList("CORS_ALLOW_ORIGINS", []string{"*"}),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAge:           getEnvInt("CORS_MAX_AGE", 600),
+
+		GitHubAPIURL:   getEnv("GITHUB_API_URL", "https://api.github.com"),
+		GitHubToken:    getEnv("GITHUB_TOKEN", ""),
+		ImportMaxFiles: getEnvInt("IMPORT_MAX_FILES", 50),
+
+		TrustedProxies: getEnvRawList("TRUSTED_PROXIES", nil),
+		ProxyHeader:    getEnv("PROXY_HEADER", "X-Forwarded-For"),
+
+		SearchRateLimit:         getEnvInt("SEARCH_RATE_LIMIT", 30),
+		SearchRateWindowSeconds: getEnvInt("SEARCH_RATE_WINDOW_SECONDS", 60),
+
+		AnonymousLikes:             getEnvBool("ANONYMOUS_LIKES", false),
+		AnonymousLikeWindowSeconds: getEnvInt("ANONYMOUS_LIKE_WINDOW_SECONDS", 3600),
+
+		APITokenTTLHours:          getEnvInt("API_TOKEN_TTL_HOURS", 720),
+		LoginMaxFailures:          getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginFailureWindowSeconds: getEnvInt("LOGIN_FAILURE_WINDOW_SECONDS", 900),
+
+		DBStatementTimeoutMs: getEnvInt("DB_STATEMENT_TIMEOUT_MS", 30000),
+		DBPrepareStmt:        getEnvBool("DB_PREPARE_STMT", true),
+		DBPrepareStmtMaxSize: getEnvInt("DB_PREPARE_STMT_MAX_SIZE", 500),
+
+		CountSnapshotIntervalMinutes: getEnvInt("COUNT_SNAPSHOT_INTERVAL_MINUTES", 60),
+		CountSnapshotRetentionDays:   getEnvInt("COUNT_SNAPSHOT_RETENTION_DAYS", 30),
+
+		PruneRetentionDays: getEnvInt("PRUNE_RETENTION_DAYS", 90),
+		PromptCacheMaxAge:  getEnvInt("PROMPT_CACHE_MAX_AGE", 60),
+		MinSearchLength:    getEnvInt("MIN_SEARCH_LENGTH", 2),
+
+		MinRoleToViewUnverified: strings.ToLower(getEnv("MIN_ROLE_TO_VIEW_UNVERIFIED", models.RoleAnonymous)),
+
+		AdvancedKeywords: getEnvList("ADVANCED_KEYWORDS", []string{
+			"dynamic programming", "memoization", "concurrency", "mutex", "deadlock",
+			"amortized", "np-hard", "segment tree", "red-black tree", "bit manipulation",
+		}),
+
+		ExpensiveOpsConcurrency: getEnvInt("EXPENSIVE_OPS_CONCURRENCY", 2),
+
+		RouteBudgetMs: getEnvInt("ROUTE_BUDGET_MS", 500),
+		RouteBudgets:  getEnvIntMap("ROUTE_BUDGETS"),
+
+		MaxDescriptionLength:      getEnvInt("MAX_DESCRIPTION_LENGTH", 5000),
+		MaxProblemStatementLength: getEnvInt("MAX_PROBLEM_STATEMENT_LENGTH", 20000),
+		MaxTagsPerPrompt:          getEnvInt("MAX_TAGS_PER_PROMPT", 10),
+
+		AllowedLanguages:     getEnvList("ALLOWED_LANGUAGES", nil),
+		CategoryDifficulties: getEnvListMap("CATEGORY_DIFFICULTIES"),
+		TitleCollation:       getEnv("TITLE_COLLATION", ""),
+		DefaultAuthorName:    getEnv("DEFAULT_AUTHOR_NAME", "Community"),
+
+		QualityWeights: models.QualityWeights{
+			Verified:     getEnvFloat("QUALITY_WEIGHT_VERIFIED", 40),
+			Engagement:   getEnvFloat("QUALITY_WEIGHT_ENGAGEMENT", 30),
+			Completeness: getEnvFloat("QUALITY_WEIGHT_COMPLETENESS", 20),
+			Freshness:    getEnvFloat("QUALITY_WEIGHT_FRESHNESS", 10),
+		},
+
+		WorkerCount:     getEnvInt("WORKER_COUNT", 4),
+		WorkerQueueSize: getEnvInt("WORKER_QUEUE_SIZE", 1000),
+
+		AllowedEmailDomains: getEnvList("ALLOWED_EMAIL_DOMAINS", nil),
+	}
+
+	cost, err := bcryptCost(config.Environment)
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.BcryptCost = cost
+
+	sort, err := defaultPromptSort()
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.DefaultPromptSort = sort
+
+	if config.APITokenTTLHours <= 0 || config.LoginMaxFailures <= 0 || config.LoginFailureWindowSeconds <= 0 {
+		log.Fatal("API_TOKEN_TTL_HOURS, LOGIN_MAX_FAILURES and LOGIN_FAILURE_WINDOW_SECONDS must be positive")
 	}
 
 	if config.DatabaseURL == "" {
 		log.Fatal("DATABASE_URL is not set")
 	}
 
+	if len(config.CORSAllowOrigins) == 0 {
+		config.CORSAllowOrigins = []string{"*"}
+	}
+	if config.CORSAllowCredentials && slices.Contains(config.CORSAllowOrigins, "*") {
+		log.Fatal("CORS_ALLOW_CREDENTIALS requires an explicit CORS_ALLOW_ORIGINS list, not *")
+	}
+
+	if !identifierPattern.MatchString(config.DBSchema) {
+		log.Fatal("DB_SCHEMA must be a lowercase identifier")
+	}
+
+	// Interpolated into ORDER BY as an identifier, so only allow collation-name characters
+	if !collationPattern.MatchString(config.TitleCollation) {
+		log.Fatal("TITLE_COLLATION contains invalid characters")
+	}
+
 	return config
 }
 
@@ -38,3 +277,125 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// bcryptCost reads BCRYPT_COST, rejecting values bcrypt itself would refuse
+// Hashing at the production cost makes every test that creates a user slow,
+// so ENVIRONMENT=test defaults to the minimum
+func bcryptCost(environment string) (int, error) {
+	defaultCost := bcrypt.DefaultCost
+	if environment == "test" {
+		defaultCost = bcrypt.MinCost
+	}
+
+	cost := getEnvInt("BCRYPT_COST", defaultCost)
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return 0, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return cost, nil
+}
+
+// defaultPromptSort reads DEFAULT_PROMPT_SORT, which has to be a sort the prompt listing accepts
+// An unknown value would otherwise only surface as a 400 on every list request that omits sort
+func defaultPromptSort() (string, error) {
+	sort := models.PromptSort(getEnv("DEFAULT_PROMPT_SORT", string(models.SortNewest)))
+	if !sort.Valid() {
+		return "", fmt.Errorf("DEFAULT_PROMPT_SORT %q must be one of newest, oldest, popular, verified_first, title, quality", sort)
+	}
+	return string(sort), nil
+}
+
+// getEnvList parses a comma-separated list, lowercased, defaultValue when unset
+func getEnvList(key string, defaultValue []string) []string {
+	if _, ok := os.LookupEnv(key); !ok {
+		return defaultValue
+	}
+
+	list := getEnvRawList(key, nil)
+	for i := range list {
+		list[i] = strings.ToLower(list[i])
+	}
+	return list
+}
+
+// getEnvRawList parses a comma-separated list, trimming entries but keeping their case
+// For values compared case-sensitively, such as origins and proxy addresses
+func getEnvRawList(key string, defaultValue []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvIntMap parses "key=1,other key=2", entries with a non-numeric value are skipped
+// "=" separates the value because keys may contain ":" (route params)
+func getEnvIntMap(key string) map[string]int {
+	result := map[string]int{}
+
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			result[name] = parsed
+		}
+	}
+
+	return result
+}
+
+// getEnvListMap parses "key:a|b,other:c" into {"key": ["a", "b"], "other": ["c"]}
+// Keys and values are trimmed and lowercased
+func getEnvListMap(key string) map[string][]string {
+	result := map[string][]string{}
+
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		name, values, found := strings.Cut(entry, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found || name == "" {
+			continue
+		}
+
+		for _, value := range strings.Split(values, "|") {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				result[name] = append(result[name], value)
+			}
+		}
+	}
+
+	return result
+}
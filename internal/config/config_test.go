@@ -0,0 +1,98 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetEnvList(t *testing.T) {
+	t.Setenv("TEST_LIST", " Go, RUST ,, python ")
+	if got := getEnvList("TEST_LIST", nil); !slices.Equal(got, []string{"go", "rust", "python"}) {
+		t.Errorf("getEnvList = %q", got)
+	}
+
+	defaults := []string{"Keep"}
+	if got := getEnvList("TEST_LIST_UNSET", defaults); !slices.Equal(got, []string{"Keep"}) || defaults[0] != "Keep" {
+		t.Errorf("unset: got %q, defaults now %q", got, defaults)
+	}
+
+	t.Setenv("TEST_LIST_EMPTY", "")
+	if got := getEnvList("TEST_LIST_EMPTY", []string{"x"}); len(got) != 0 {
+		t.Errorf("set but empty: got %q, want no entries", got)
+	}
+}
+
+func TestGetEnvRawListKeepsCase(t *testing.T) {
+	t.Setenv("TEST_ORIGINS", "https://App.example.com/Path, fe80::1%Eth0")
+	want := []string{"https://App.example.com/Path", "fe80::1%Eth0"}
+	if got := getEnvRawList("TEST_ORIGINS", nil); !slices.Equal(got, want) {
+		t.Errorf("getEnvRawList = %q, want %q", got, want)
+	}
+}
+
+func TestBcryptCost(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		env         string
+		want        int
+		wantErr     bool
+	}{
+		{name: "default", environment: "production", want: bcrypt.DefaultCost},
+		{name: "test default", environment: "test", want: bcrypt.MinCost},
+		{name: "explicit", environment: "production", env: "12", want: 12},
+		{name: "below min", environment: "production", env: "3", wantErr: true},
+		{name: "above max", environment: "production", env: "32", wantErr: true},
+		{name: "not a number falls back", environment: "production", env: "high", want: bcrypt.DefaultCost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BCRYPT_COST", tt.env)
+
+			got, err := bcryptCost(tt.environment)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("bcryptCost = %d, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("bcryptCost = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultPromptSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: "newest"},
+		{name: "explicit", env: "popular", want: "popular"},
+		{name: "unknown", env: "random", wantErr: true},
+		{name: "wrong case", env: "Quality", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEFAULT_PROMPT_SORT", tt.env)
+
+			got, err := defaultPromptSort()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("defaultPromptSort = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("defaultPromptSort = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
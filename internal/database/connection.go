@@ -1,9 +1,13 @@
 package database
 
 import (
+	"PromptGallery/internal/config"
 	"PromptGallery/internal/models"
 	"fmt"
 	"log"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -13,21 +17,43 @@ import (
 
 var DB *gorm.DB
 
-func ConnectDatabase(dtabaseURL string, environment string) error {
+func ConnectDatabase(cfg *config.Config) error {
 	var err error
 
 	config := &gorm.Config{
-		Logger:                                   getLoggerConfig(environment),
+		Logger:                                   getLoggerConfig(cfg.Environment),
 		DisableForeignKeyConstraintWhenMigrating: true,
+
+		// Reuse prepared statements instead of re-parsing every query. database/sql prepares each
+		// one lazily on the pooled connections that run it; entries are LRU-bounded and expire hourly
+		PrepareStmt:        cfg.DBPrepareStmt,
+		PrepareStmtMaxSize: cfg.DBPrepareStmtMaxSize,
+		PrepareStmtTTL:     time.Hour,
 	}
 
-	DB, err = gorm.Open(postgres.Open(dtabaseURL), config)
+	// Models name their tables explicitly, so the schema is selected through search_path
+	// rather than a NamingStrategy prefix. Every pooled connection gets it from the DSN
+	dsn := WithRuntimeParam(cfg.DatabaseURL, "search_path", cfg.DBSchema)
+
+	// Kill runaway queries (e.g. unbounded LIKE searches) at the database, whatever the caller's context does
+	if cfg.DBStatementTimeoutMs > 0 {
+		dsn = WithRuntimeParam(dsn, "statement_timeout", strconv.Itoa(cfg.DBStatementTimeoutMs))
+	}
+
+	DB, err = gorm.Open(postgres.Open(dsn), config)
 
 	if err != nil {
 		log.Printf("❌ Database connection failed: %v", err)
 		return err
 	}
 
+	if cfg.DBSchema != "public" {
+		if err := DB.Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, cfg.DBSchema)).Error; err != nil {
+			log.Printf("❌ Failed to create schema %s: %v", cfg.DBSchema, err)
+			return err
+		}
+	}
+
 	sqlDB, err := DB.DB()
 	if err != nil {
 		log.Printf("❌ Failed to get SQL DB instance: %v", err)
@@ -40,7 +66,12 @@ func ConnectDatabase(dtabaseURL string, environment string) error {
 	sqlDB.SetMaxOpenConns(100) // Maximum open connections
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
-	if err != nil {
+	if !cfg.DBAutoMigrate {
+		log.Println("⏭️ Skipping database migrations (DB_AUTO_MIGRATE=false)")
+		return nil
+	}
+
+	if err := Migrate(DB); err != nil {
 		log.Printf("❌ Database migration failed: %v", err)
 		return err
 	}
@@ -58,18 +89,61 @@ func getLoggerConfig(environment string) logger.Interface {
 	return logger.Default.LogMode(logger.Info)
 }
 
+// WithRuntimeParam adds a Postgres run-time parameter to either DSN style:
+// URLs (postgres://...) get a query parameter, key=value strings get another pair
+func WithRuntimeParam(dsn, key, value string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err == nil {
+			query := parsed.Query()
+			query.Set(key, value)
+			parsed.RawQuery = query.Encode()
+			return parsed.String()
+		}
+	}
+	return fmt.Sprintf("%s %s=%s", dsn, key, value)
+}
+
 func GetDb() *gorm.DB {
 	return DB
 }
 
-func autoMigrate() error {
+// Migrate brings db's schema up to date, also used by testdb to set up test schemas
+func Migrate(db *gorm.DB) error {
 	log.Println("🔄 Running database migrations...")
 
-	return DB.AutoMigrate(
+	if err := db.AutoMigrate(
 		&models.Prompt{},
+		&models.PromptCount{},
+		&models.PromptCountSnapshot{},
+		&models.PromptLike{},
+		&models.PromptTranslation{},
 		&models.User{},
+		&models.APIToken{},
 		&models.PromptRequest{},
-	)
+		&models.SavedView{},
+		&models.AuditEntry{},
+	); err != nil {
+		return err
+	}
+
+	// Carry over counters from when they were stored on the prompts table, then drop the old
+	// columns in the same transaction so the copy runs once and can't revive merged-away rows
+	if db.Migrator().HasColumn("prompts", "view_count") {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(`INSERT INTO prompt_counts (prompt_id, view_count, like_count)
+				SELECT id, view_count, like_count FROM prompts
+				ON CONFLICT (prompt_id) DO NOTHING`).Error; err != nil {
+				return err
+			}
+			return tx.Exec("ALTER TABLE prompts DROP COLUMN view_count, DROP COLUMN IF EXISTS like_count").Error
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Case-insensitive uniqueness for emails, also covers rows saved before emails were normalized
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email))").Error
 }
 
 func CloseDatabase() error {
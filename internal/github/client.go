@@ -0,0 +1,143 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxFileSize skips anything larger, prompts are a few KB of Markdown
+const maxFileSize = 1 << 20
+
+// ErrNotFound is returned when the repository, path or ref doesn't exist (or isn't visible to the token)
+var ErrNotFound = errors.New("repository or path not found")
+
+// Client reads repository contents through the GitHub REST API
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient talks to baseURL (https://api.github.com, or a GitHub Enterprise API root)
+// token is optional, without it only public repositories are readable and rate limits are low
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// File is a fetched repository file
+type File struct {
+	Path    string
+	Content string
+}
+
+type contentEntry struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+// MarkdownFiles fetches the .md files directly inside dir (subdirectories aren't walked)
+// dir may also name a single Markdown file. ref is a branch, tag or sha, "" for the default branch
+// More than limit Markdown files is an error rather than a silent partial import
+func (c *Client) MarkdownFiles(ctx context.Context, owner, repo, dir, ref string, limit int) ([]File, error) {
+	body, err := c.get(ctx, c.contentsURL(owner, repo, dir, ref), "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []contentEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		// A file path answers with a single object instead of a listing
+		var entry contentEntry
+		if err := json.Unmarshal(body, &entry); err != nil {
+			return nil, fmt.Errorf("unexpected contents response: %w", err)
+		}
+		entries = []contentEntry{entry}
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type == "file" && isMarkdown(entry.Path) && entry.Size <= maxFileSize {
+			paths = append(paths, entry.Path)
+		}
+	}
+	if limit > 0 && len(paths) > limit {
+		return nil, fmt.Errorf("invalid path: %d markdown files found, at most %d can be imported at once", len(paths), limit)
+	}
+
+	files := make([]File, 0, len(paths))
+	for _, filePath := range paths {
+		content, err := c.get(ctx, c.contentsURL(owner, repo, filePath, ref), "application/vnd.github.raw")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", filePath, err)
+		}
+		files = append(files, File{Path: filePath, Content: string(content)})
+	}
+
+	return files, nil
+}
+
+func (c *Client) contentsURL(owner, repo, filePath, ref string) string {
+	u := fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.baseURL,
+		url.PathEscape(owner), url.PathEscape(repo), escapePath(strings.Trim(filePath, "/")))
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+	return u
+}
+
+func (c *Client) get(ctx context.Context, u, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("github returned status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+}
+
+// escapePath escapes each segment but keeps the slashes between them
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func isMarkdown(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteNotFound is the catch-all for unknown routes, in the standard envelope
+// A path that exists under other methods gets 405 instead, with fiber filling the Allow header
+func RouteNotFound(c *fiber.Ctx) error {
+	// Nothing is registered after the catch-all, so Next only reports why routing failed
+	var routeErr *fiber.Error
+	if err := c.Next(); errors.As(err, &routeErr) && routeErr.Code == fiber.StatusMethodNotAllowed {
+		return c.Status(fiber.StatusMethodNotAllowed).JSON(APIResponse{
+			Status:  "error",
+			Message: "Method not allowed",
+			Code:    "method_not_allowed",
+		})
+	}
+
+	return c.Status(404).JSON(APIResponse{
+		Status:  "error",
+		Message: "Route not found",
+		Code:    "route_not_found",
+	})
+}
+
+// currentUser returns the authenticated user that auth middleware stored under
+// the "user" local, or nil for anonymous requests
+func currentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals("user").(*models.User)
+	return user
+}
+
+// listCount fills APIResponse.Count, a pointer so an empty list still reports 0
+func listCount(n int) *int {
+	return &n
+}
+
+// hidesUnverified reports whether the caller ranks below MIN_ROLE_TO_VIEW_UNVERIFIED
+func hidesUnverified(cfg *config.Config, user *models.User) bool {
+	if cfg.MinRoleToViewUnverified == models.RoleAnonymous {
+		return false
+	}
+	return user == nil || !user.Role.AtLeast(models.UserRole(cfg.MinRoleToViewUnverified))
+}
+
+// requestedLocales lists the locales the client prefers, best first
+// An explicit ?locale= wins, followed by the Accept-Language entries ordered by q-value
+func requestedLocales(c *fiber.Ctx) []string {
+	var locales []string
+	if locale := c.Query("locale"); locale != "" {
+		locales = append(locales, locale)
+	}
+
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var accepted []weighted
+
+	for _, part := range strings.Split(c.Get(fiber.HeaderAcceptLanguage), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			accepted = append(accepted, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool { return accepted[i].q > accepted[j].q })
+	for _, entry := range accepted {
+		locales = append(locales, entry.tag)
+	}
+
+	return locales
+}
+
+// bindPagination reads ?page= and ?limit=, defaulting absent values
+// Invalid or out-of-range values are reported into fieldErrors rather than silently replaced
+func bindPagination(c *fiber.Ctx, fieldErrors map[string]string) models.PaginationParams {
+	page, err := parseIntQuery(c, "page", models.DefaultPage)
+	if err != nil {
+		fieldErrors["page"] = err.Error()
+	}
+
+	limit, err := parseIntQuery(c, "limit", models.DefaultLimit)
+	if err != nil {
+		fieldErrors["limit"] = err.Error()
+	} else if limit > models.MaxLimit {
+		fieldErrors["limit"] = fmt.Sprintf("limit must be at most %d", models.MaxLimit)
+	}
+
+	return models.PaginationParams{Page: page, Limit: limit}
+}
+
+// parseIntQuery reads a positive integer query parameter, defaultValue when absent
+func parseIntQuery(c *fiber.Ctx, key string, defaultValue int) (int, error) {
+	valueStr := c.Query(key)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return defaultValue, fmt.Errorf("%s must be a number", key)
+	}
+	if value < 1 {
+		return defaultValue, fmt.Errorf("%s must be at least 1", key)
+	}
+
+	return value, nil
+}
+
+// setCreatedLocation points the Location header at the resource just created
+// under the collection path the request was posted to
+func setCreatedLocation(c *fiber.Ctx, id uint) {
+	c.Location(fmt.Sprintf("%s/%d", strings.TrimSuffix(c.Path(), "/"), id))
+}
+
+// BodyError describes why a request body could not be decoded
+// Field is empty when the error isn't tied to a single field (e.g. malformed JSON)
+type BodyError struct {
+	Field   string
+	Message string
+}
+
+func (e *BodyError) Error() string {
+	return e.Message
+}
+
+// parseBody decodes the request body into out
+// JSON bodies go through json.Decoder so type mismatches can be reported per field,
+// other content types fall back to fiber's BodyParser
+func parseBody(c *fiber.Ctx, out interface{}, disallowUnknown bool) error {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		return c.BodyParser(out)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+	if disallowUnknown {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(out); err != nil {
+		return toBodyError(err)
+	}
+	return nil
+}
+
+func toBodyError(err error) *BodyError {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &typeErr):
+		return &BodyError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, describeKind(typeErr.Type.Kind())),
+		}
+	case errors.As(err, &syntaxErr):
+		return &BodyError{Message: fmt.Sprintf("malformed JSON at position %d", syntaxErr.Offset)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &BodyError{Field: field, Message: fmt.Sprintf("%s is not a recognized field", field)}
+	case errors.Is(err, io.EOF):
+		return &BodyError{Message: "request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &BodyError{Message: "malformed JSON: body ends early"}
+	}
+
+	return &BodyError{Message: err.Error()}
+}
+
+func describeKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+// bodyErrorResponse builds the 400 response for a body that failed to decode
+func bodyErrorResponse(err error) APIResponse {
+	response := APIResponse{
+		Status:  "error",
+		Message: "Invalid request body",
+		Error:   err.Error(),
+	}
+
+	var bodyErr *BodyError
+	if errors.As(err, &bodyErr) && bodyErr.Field != "" {
+		response.Errors = map[string]string{bodyErr.Field: bodyErr.Message}
+	}
+
+	return response
+}
+
+// validationErrorResponse builds the 400 response for a business-rule violation
+func validationErrorResponse(err *services.ValidationError) APIResponse {
+	return APIResponse{
+		Status:  "error",
+		Message: "Validation failed",
+		Error:   err.Message,
+		Errors:  map[string]string{err.Field: err.Message},
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type bodyTarget struct {
+	Title string   `json:"title"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+	Draft bool     `json:"draft"`
+}
+
+// runParseBody posts body to a throwaway app and returns what parseBody made of it
+func runParseBody(t *testing.T, contentType, body string, disallowUnknown bool) (bodyTarget, error) {
+	t.Helper()
+
+	var target bodyTarget
+	var parseErr error
+
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		parseErr = parseBody(c, &target, disallowUnknown)
+		return c.SendStatus(204)
+	})
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, contentType)
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	return target, parseErr
+}
+
+func TestParseBody(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		disallowUnknown bool
+		wantField       string
+		wantMessage     string
+	}{
+		{name: "valid", body: `{"title": "Two Sum", "count": 2}`},
+		{name: "number as string", body: `{"count": "two"}`, wantField: "count", wantMessage: "count must be a number"},
+		{name: "string as number", body: `{"title": 5}`, wantField: "title", wantMessage: "title must be a string"},
+		{name: "array as string", body: `{"tags": "go"}`, wantField: "tags", wantMessage: "tags must be an array"},
+		{name: "boolean as string", body: `{"draft": "yes"}`, wantField: "draft", wantMessage: "draft must be a boolean"},
+		{name: "truncated", body: `{"title": `, wantMessage: "malformed JSON: body ends early"},
+		{name: "syntax error", body: `{"title" "x"}`, wantMessage: "malformed JSON at position 10"},
+		{name: "empty", body: ``, wantMessage: "request body is empty"},
+		{name: "unknown field allowed", body: `{"color": "red"}`},
+		{name: "unknown field rejected", body: `{"color": "red"}`, disallowUnknown: true, wantField: "color", wantMessage: "color is not a recognized field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runParseBody(t, fiber.MIMEApplicationJSON, tt.body, tt.disallowUnknown)
+
+			if tt.wantMessage == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			bodyErr, ok := err.(*BodyError)
+			if !ok {
+				t.Fatalf("error = %v (%T), want *BodyError", err, err)
+			}
+			if bodyErr.Field != tt.wantField || bodyErr.Message != tt.wantMessage {
+				t.Errorf("got field %q message %q, want field %q message %q", bodyErr.Field, bodyErr.Message, tt.wantField, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseBodyDecodesJSON(t *testing.T) {
+	target, err := runParseBody(t, "application/json; charset=utf-8", `{"title": "Two Sum", "count": 2, "tags": ["arrays"]}`, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Title != "Two Sum" || target.Count != 2 || len(target.Tags) != 1 {
+		t.Errorf("decoded %+v", target)
+	}
+}
+
+func TestBodyErrorResponse(t *testing.T) {
+	response := bodyErrorResponse(&BodyError{Field: "count", Message: "count must be a number"})
+	if response.Errors["count"] != "count must be a number" {
+		t.Errorf("errors = %v, want the count field", response.Errors)
+	}
+
+	response = bodyErrorResponse(&BodyError{Message: "request body is empty"})
+	if response.Errors != nil {
+		t.Errorf("errors = %v, want none for a body-wide error", response.Errors)
+	}
+	if response.Error != "request body is empty" {
+		t.Errorf("error = %q", response.Error)
+	}
+}
+
+func TestRequestedLocales(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           []string
+	}{
+		{name: "none"},
+		{name: "ordered by q", acceptLanguage: "en;q=0.5, fr-CA, de;q=0.8", want: []string{"fr-CA", "de", "en"}},
+		{name: "query first", query: "es", acceptLanguage: "fr", want: []string{"es", "fr"}},
+		{name: "wildcard and q=0 dropped", acceptLanguage: "*, it;q=0, pt", want: []string{"pt"}},
+		{name: "ties keep header order", acceptLanguage: "nl, sv", want: []string{"nl", "sv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got = requestedLocales(c)
+				return nil
+			})
+
+			target := "/"
+			if tt.query != "" {
+				target += "?locale=" + tt.query
+			}
+			req := httptest.NewRequest("GET", target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set(fiber.HeaderAcceptLanguage, tt.acceptLanguage)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("requestedLocales = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		want       models.PaginationParams
+		wantErrors []string
+	}{
+		{name: "defaults", want: models.PaginationParams{Page: models.DefaultPage, Limit: models.DefaultLimit}},
+		{name: "explicit", query: "?page=4&limit=50", want: models.PaginationParams{Page: 4, Limit: 50}},
+		{name: "limit at max", query: "?limit=100", want: models.PaginationParams{Page: 1, Limit: 100}},
+		{name: "limit past max", query: "?limit=101", wantErrors: []string{"limit"}},
+		{name: "not numbers", query: "?page=two&limit=ten", wantErrors: []string{"page", "limit"}},
+		{name: "zero page", query: "?page=0", wantErrors: []string{"page"}},
+		{name: "negative limit", query: "?limit=-3", wantErrors: []string{"limit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.PaginationParams
+			fieldErrors := map[string]string{}
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				got = bindPagination(c, fieldErrors)
+				return nil
+			})
+			if _, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil)); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			if len(fieldErrors) != len(tt.wantErrors) {
+				t.Fatalf("field errors = %v, want errors on %v", fieldErrors, tt.wantErrors)
+			}
+			for _, field := range tt.wantErrors {
+				if fieldErrors[field] == "" {
+					t.Errorf("no error reported for %s: %v", field, fieldErrors)
+				}
+			}
+			if len(tt.wantErrors) == 0 && got != tt.want {
+				t.Errorf("bindPagination = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newRoutingApp registers one prompt route and the catch-all the way main does
+func newRoutingApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/api/v1/prompts/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	app.Use("*", RouteNotFound)
+	return app
+}
+
+func decodeEnvelope(t *testing.T, resp *http.Response) APIResponse {
+	t.Helper()
+
+	var body APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return body
+}
+
+func TestRouteNotFound(t *testing.T) {
+	resp, err := newRoutingApp().Test(httptest.NewRequest("GET", "/api/v1/nothing-here", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+
+	body := decodeEnvelope(t, resp)
+	if body.Status != "error" || body.Code != "route_not_found" || body.Message != "Route not found" {
+		t.Errorf("body = %+v, want the error envelope with code route_not_found", body)
+	}
+}
+
+func TestRouteNotFoundMethodNotAllowed(t *testing.T) {
+	resp, err := newRoutingApp().Test(httptest.NewRequest("DELETE", "/api/v1/prompts/7", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 405 {
+		t.Errorf("status = %d, want 405", resp.StatusCode)
+	}
+	if allow := resp.Header.Get(fiber.HeaderAllow); !strings.Contains(allow, "GET") {
+		t.Errorf("Allow = %q, want it to list GET", allow)
+	}
+
+	body := decodeEnvelope(t, resp)
+	if body.Status != "error" || body.Code != "method_not_allowed" {
+		t.Errorf("body = %+v, want the error envelope with code method_not_allowed", body)
+	}
+}
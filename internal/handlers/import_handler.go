@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	importService *services.ImportService
+	cfg           *config.Config
+}
+
+func NewImportHandler(importService *services.ImportService, cfg *config.Config) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		cfg:           cfg,
+	}
+}
+
+// ImportFromGitHub creates prompts from a directory of Markdown files in a GitHub repository
+// Files that fail to parse or validate are reported per file, the rest are still imported
+func (h *ImportHandler) ImportFromGitHub(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	var importReq models.GitHubImportRequest
+
+	if err := parseBody(c, &importReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	result, err := h.importService.ImportFromGitHub(c.UserContext(), &importReq, user)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Repository or path not found",
+			})
+		case strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "invalid"):
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(502).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to fetch from GitHub",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Imported %d prompts, %d failed", len(result.Created), len(result.Failed)),
+		Data:    result,
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EnumValues is every value clients may send or receive for the API's enums
+type EnumValues struct {
+	Difficulties    []models.DifficultyLevel `json:"difficulties"`
+	RequestStatuses []models.RequestStatus   `json:"request_statuses"`
+	Priorities      []models.Priority        `json:"priorities"`
+	Roles           []models.UserRole        `json:"roles"`
+}
+
+// GetEnums serves the enum values so frontends can build dropdowns instead of hardcoding them
+// The lists are the same slices the models' Valid() methods check against
+func GetEnums(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Enums fetched successfully",
+		Data: EnumValues{
+			Difficulties:    models.DifficultyLevels,
+			RequestStatuses: models.RequestStatuses,
+			Priorities:      models.Priorities,
+			Roles:           models.UserRoles,
+		},
+	})
+}
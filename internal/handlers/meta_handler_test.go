@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetEnums(t *testing.T) {
+	app := fiber.New()
+	app.Get("/meta/enums", GetEnums)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/meta/enums", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if resp.Header.Get(fiber.HeaderCacheControl) == "" {
+		t.Error("enums served without Cache-Control")
+	}
+
+	var body struct {
+		Data EnumValues `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if !slices.Equal(body.Data.Difficulties, models.DifficultyLevels) {
+		t.Errorf("difficulties = %v", body.Data.Difficulties)
+	}
+	if !slices.Equal(body.Data.RequestStatuses, models.RequestStatuses) {
+		t.Errorf("request statuses = %v", body.Data.RequestStatuses)
+	}
+	if !slices.Equal(body.Data.Priorities, models.Priorities) {
+		t.Errorf("priorities = %v", body.Data.Priorities)
+	}
+	if !slices.Equal(body.Data.Roles, models.UserRoles) {
+		t.Errorf("roles = %v", body.Data.Roles)
+	}
+}
+
+// Every listed value must pass its own Valid(), and nothing outside the lists may
+func TestEnumListsMatchValid(t *testing.T) {
+	for _, d := range models.DifficultyLevels {
+		if !d.Valid() {
+			t.Errorf("difficulty %q listed but not valid", d)
+		}
+	}
+	for _, s := range models.RequestStatuses {
+		if !s.Valid() {
+			t.Errorf("request status %q listed but not valid", s)
+		}
+	}
+	for _, p := range models.Priorities {
+		if !p.Valid() {
+			t.Errorf("priority %q listed but not valid", p)
+		}
+	}
+	for _, r := range models.UserRoles {
+		if !r.Valid() {
+			t.Errorf("role %q listed but not valid", r)
+		}
+	}
+
+	if models.DifficultyLevel("legendary").Valid() || models.RequestStatus("done").Valid() ||
+		models.Priority("critical").Valid() || models.UserRole(models.RoleAnonymous).Valid() {
+		t.Error("an unlisted value passed Valid()")
+	}
+}
@@ -1,59 +1,823 @@
 package handlers
 
 import (
+	"PromptGallery/internal/config"
 	"PromptGallery/internal/models"
 	"PromptGallery/internal/services"
+	"errors"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type PromptHandler struct {
 	promptService *services.PromptService
+	cfg           *config.Config
 }
 
-func NewPromptHandler(promptService *services.PromptService) *PromptHandler {
+func NewPromptHandler(promptService *services.PromptService, cfg *config.Config) *PromptHandler {
 	return &PromptHandler{
 		promptService: promptService,
+		cfg:           cfg,
 	}
 }
 
+const mimeMarkdown = "text/markdown"
+
+// APIResponse is the envelope every endpoint responds with
 type APIResponse struct {
-	Status  string
-	Message string
-	Data    interface{}
-	Error   string
+	Status  string            `json:"status"` // "success" or "error"
+	Message string            `json:"message,omitempty"`
+	Data    interface{}       `json:"data,omitempty"`
+	Count   *int              `json:"count,omitempty"` // Items in this response on list endpoints, unlike total across pages
+	Error   string            `json:"error,omitempty"`
+	Code    string            `json:"code,omitempty"`   // Machine-readable error code, e.g. "route_not_found"
+	Errors  map[string]string `json:"errors,omitempty"` // field -> message
+}
+
+func (h *PromptHandler) GetPrompts(c *fiber.Ctx) error {
+
+	filter, pagination, fieldErrors := h.parsePromptQuery(c)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter.VerifiedOnly = hidesUnverified(h.cfg, currentUser(c))
+
+	result, err := h.promptService.GetAllPrompts(filter, pagination)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// GetMyPrompts lists the authenticated user's own prompts, unverified ones included
+// ?status=verified|unverified narrows the list, the other list filters apply as usual
+func (h *PromptHandler) GetMyPrompts(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	filter, pagination, fieldErrors := h.parsePromptQuery(c)
+
+	switch c.Query("status") {
+	case "":
+	case "verified", "unverified":
+		verified := c.Query("status") == "verified"
+		filter.IsVerified = &verified
+	default:
+		fieldErrors["status"] = "status must be one of verified, unverified"
+	}
+
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter.AuthorID = &user.ID
+
+	result, err := h.promptService.GetAllPrompts(filter, pagination)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// GetPendingVerification is the moderators' work queue: unverified prompts, oldest first
+// The usual list filters apply, is_verified and sort are fixed
+func (h *PromptHandler) GetPendingVerification(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanVerifyPrompts() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can view the verification queue",
+		})
+	}
+
+	filter, pagination, fieldErrors := h.parsePromptQuery(c)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	unverified := false
+	filter.IsVerified = &unverified
+	filter.Sort = models.SortOldest
+	filter.Ordered = false
+
+	result, err := h.promptService.GetAllPrompts(filter, pagination)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// GetStudyMix serves a page balanced across difficulties instead of one long recency list
+// The list filters apply except difficulty, ?limit= sizes the page
+func (h *PromptHandler) GetStudyMix(c *fiber.Ctx) error {
+	filter, pagination, fieldErrors := h.parsePromptQuery(c)
+	if filter.Difficulty != "" {
+		fieldErrors["difficulty"] = "difficulty can't be used with the study mix"
+	}
+	if filter.Ordered || filter.Seed != "" {
+		fieldErrors["sort"] = "the study mix doesn't support ordered or seed"
+	}
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter.VerifiedOnly = hidesUnverified(h.cfg, currentUser(c))
+
+	prompts, err := h.promptService.GetStudyMix(filter, pagination.Limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts fetched successfully",
+		Data:    prompts,
+		Count:   listCount(len(prompts)),
+	})
+}
+
+// GetDailyPrompt serves the prompt of the day, ?difficulty= narrows the pool
+func (h *PromptHandler) GetDailyPrompt(c *fiber.Ctx) error {
+	prompt, err := h.promptService.GetDailyPrompt(models.DifficultyLevel(c.Query("difficulty")), time.Now())
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		if strings.Contains(err.Error(), "no prompts found") {
+			return c.Status(404).JSON(APIResponse{
+				Status:  "error",
+				Message: "No prompt available for the daily challenge",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Daily prompt fetched successfully",
+		Data:    prompt,
+	})
+}
+
+// GetCountDeltas serves ?ids=1,2&since=<RFC 3339>, the view/like change per prompt since then
+func (h *PromptHandler) GetCountDeltas(c *fiber.Ctx) error {
+	fieldErrors := map[string]string{}
+
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil {
+		fieldErrors["ids"] = err.Error()
+	}
+
+	since, err := time.Parse(time.RFC3339, c.Query("since"))
+	if err != nil {
+		fieldErrors["since"] = "since must be an RFC 3339 timestamp"
+	}
+
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	deltas, err := h.promptService.GetCountDeltas(ids, since, hidesUnverified(h.cfg, currentUser(c)))
+	if err != nil {
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Count deltas fetched successfully",
+		Data:    deltas,
+		Count:   listCount(len(deltas)),
+	})
+}
+
+func (h *PromptHandler) CountPrompts(c *fiber.Ctx) error {
+	filter, _, fieldErrors := h.parsePromptQuery(c)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter.VerifiedOnly = hidesUnverified(h.cfg, currentUser(c))
+
+	result, err := h.promptService.CountPrompts(filter)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts counted successfully",
+		Data:    result,
+	})
+}
+
+func (h *PromptHandler) GetPromptByID(c *fiber.Ctx) error {
+	id, err := h.parseUintParam(c, "id")
+
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	opts := services.PromptDetailOptions{
+		Locales:      requestedLocales(c),
+		VerifiedOnly: hidesUnverified(h.cfg, currentUser(c)),
+	}
+
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(include) {
+		case "":
+		case "verifier":
+			opts.IncludeVerifier = true
+		default:
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Errors:  map[string]string{"include": "include must be a comma-separated list of: verifier"},
+			})
+		}
+	}
+
+	// Soft-deleted prompts are only visible to moderators, everyone else gets the usual 404
+	if includeStr := c.Query("include_deleted"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Errors:  map[string]string{"include_deleted": "include_deleted must be true or false"},
+			})
+		}
+		if user := currentUser(c); include && user != nil && user.Role.CanVerifyPrompts() {
+			opts.IncludeDeleted = true
+		}
+	}
+
+	prompt, err := h.promptService.GetPromptByID(id, opts)
+	if err != nil {
+		return c.Status(404).JSON(APIResponse{
+			Status:  "error",
+			Message: "Prompt not found",
+		})
+	}
+
+	h.setCacheHeaders(c)
+	c.Set(fiber.HeaderETag, prompt.ETag)
+
+	if c.Accepts(fiber.MIMEApplicationJSON, mimeMarkdown) == mimeMarkdown {
+		return sendPromptMarkdown(c, prompt)
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt fetched successfully",
+		Data:    prompt,
+	})
+
+}
+
+// GetPromptMarkdown serves GET /prompts/:id.md, the prompt as a downloadable Markdown file
+func (h *PromptHandler) GetPromptMarkdown(c *fiber.Ctx) error {
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	prompt, err := h.promptService.GetPromptByID(id, services.PromptDetailOptions{
+		Locales:      requestedLocales(c),
+		VerifiedOnly: hidesUnverified(h.cfg, currentUser(c)),
+	})
+	if err != nil {
+		return c.Status(404).JSON(APIResponse{
+			Status:  "error",
+			Message: "Prompt not found",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return sendPromptMarkdown(c, prompt)
+}
+
+func sendPromptMarkdown(c *fiber.Ctx, prompt *services.PromptResponse) error {
+	c.Attachment(fmt.Sprintf("prompt-%d.md", prompt.ID))
+	c.Set(fiber.HeaderContentType, mimeMarkdown+"; charset=utf-8")
+	return c.Status(200).SendString(services.RenderPromptMarkdown(prompt))
+}
+
+func (h *PromptHandler) CreatePrompt(c *fiber.Ctx) error {
+
+	var createReq models.PromptCreateRequest
+
+	if err := parseBody(c, &createReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	prompt, err := h.promptService.CreatePrompt(&createReq)
+
+	if err != nil {
+		// Handle validation errors
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to create prompt",
+		})
+	}
+
+	setCreatedLocation(c, prompt.ID)
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt created successfully",
+		Data:    prompt,
+	})
+
+}
+
+// ValidatePrompt checks a create body without saving it, for inline form feedback
+// Invalid bodies get the same 400 response as POST /prompts
+func (h *PromptHandler) ValidatePrompt(c *fiber.Ctx) error {
+	var createReq models.PromptCreateRequest
+
+	if err := parseBody(c, &createReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	result, err := h.promptService.ValidatePrompt(&createReq)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to validate prompt",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt is valid",
+		Data:    result,
+	})
+}
+
+// UpsertByExternalID creates or updates the prompt keyed by an integrator's own id
+// Responds 201 with a Location on create and 200 on update, so syncs can be replayed safely
+// Integrators and moderators only, anyone else could overwrite a synced prompt by guessing its id
+func (h *PromptHandler) UpsertByExternalID(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanSyncPrompts() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only integrators and moderators can sync prompts",
+		})
+	}
+
+	var upsertReq models.PromptCreateRequest
+
+	if err := parseBody(c, &upsertReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	prompt, created, err := h.promptService.UpsertPromptByExternalID(c.Params("external_id"), &upsertReq)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to save prompt",
+		})
+	}
+
+	if !created {
+		return c.Status(200).JSON(APIResponse{
+			Status:  "success",
+			Message: "Prompt updated successfully",
+			Data:    prompt,
+		})
+	}
+
+	// Point at the canonical /prompts/:id rather than the external path
+	collection, _, _ := strings.Cut(c.Path(), "/external/")
+	c.Location(fmt.Sprintf("%s/%d", collection, prompt.ID))
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt created successfully",
+		Data:    prompt,
+	})
+}
+
+// UpdatePrompt edits the fields sent in the body, leaving the others as they are
+// Only the prompt's author or a moderator may edit it
+func (h *PromptHandler) UpdatePrompt(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	var updateReq models.PromptUpdateRequest
+
+	if err := parseBody(c, &updateReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	prompt, err := h.promptService.UpdatePrompt(id, &updateReq, user)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		if strings.Contains(err.Error(), "not allowed") {
+			return c.Status(403).JSON(APIResponse{
+				Status: "error",
+				Error:  "Only the author or a moderator can edit this prompt",
+			})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to update prompt",
+		})
+	}
+
+	c.Set(fiber.HeaderETag, prompt.ETag)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt updated successfully",
+		Data:    prompt,
+	})
+}
+
+func (h *PromptHandler) DeletePrompt(c *fiber.Ctx) error {
+	// Parse path parameter
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	// Call service, with If-Match the prompt is only deleted if it's unchanged since the client read it
+	if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" {
+		err = h.promptService.DeletePromptIfMatch(id, ifMatch)
+	} else {
+		err = h.promptService.DeletePrompt(id)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		if strings.Contains(err.Error(), "precondition failed") {
+			return c.Status(412).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt was modified since it was fetched",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to delete prompt",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompt deleted successfully",
+	})
+}
+
+// ToggleLike flips the caller's like on a prompt and returns the new state and count
+// With ANONYMOUS_LIKES on, callers without an account add a like deduplicated by IP
+func (h *PromptHandler) ToggleLike(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil && !h.cfg.AnonymousLikes {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	var result *services.LikeToggleResponse
+	if user == nil {
+		result, err = h.promptService.AnonymousLike(id, c.IP(), hidesUnverified(h.cfg, user))
+	} else {
+		result, err = h.promptService.ToggleLike(id, user.ID, hidesUnverified(h.cfg, user))
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to toggle like",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Like toggled successfully",
+		Data:    result,
+	})
 }
 
-func (h *PromptHandler) GetPrompts(c *fiber.Ctx) error {
+// VerifyPrompt marks a prompt as verified by the calling moderator
+func (h *PromptHandler) VerifyPrompt(c *fiber.Ctx) error {
+	return h.setVerification(c, true)
+}
+
+// UnverifyPrompt sends a verified prompt back to the verification queue, the body gives the reason
+func (h *PromptHandler) UnverifyPrompt(c *fiber.Ctx) error {
+	return h.setVerification(c, false)
+}
+
+func (h *PromptHandler) setVerification(c *fiber.Ctx, verified bool) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanVerifyPrompts() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can verify prompts",
+		})
+	}
 
-	filter, page, limit, err := h.parsePromptQuery(c)
+	id, err := h.parseUintParam(c, "id")
 	if err != nil {
 		return c.Status(400).JSON(APIResponse{
-			Status:  "error",
-			Message: "Invalid query parameters",
-			Error:   err.Error(),
+			Status: "error",
+			Error:  "Invalid prompt ID",
 		})
 	}
 
-	result, err := h.promptService.GetAllPrompts(filter, page, limit)
+	var prompt *services.PromptResponse
+	message := "Prompt verified successfully"
+	if verified {
+		prompt, err = h.promptService.VerifyPrompt(id, user.ID)
+	} else {
+		var unverifyReq models.PromptUnverifyRequest
+		if err := parseBody(c, &unverifyReq, h.cfg.StrictJSONBody); err != nil {
+			return c.Status(400).JSON(bodyErrorResponse(err))
+		}
+
+		prompt, err = h.promptService.UnverifyPrompt(id, user.ID, unverifyReq.Reason)
+		message = "Prompt unverified successfully"
+	}
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
 		return c.Status(500).JSON(APIResponse{
-			Status:  "error",
-			Message: "Internal server error",
+			Status: "error",
+			Error:  "Failed to update verification",
 		})
 	}
 
 	return c.Status(200).JSON(APIResponse{
 		Status:  "success",
-		Message: "Prompts fetched successfully",
-		Data:    result,
+		Message: message,
+		Data:    prompt,
 	})
 }
 
-func (h *PromptHandler) GetPromptByID(c *fiber.Ctx) error {
-	id, err := h.parseUintParam(c, "id")
+// LikePrompt likes a prompt and returns the new count, repeating it doesn't add another like
+// Anonymous callers are handled as in ToggleLike (ANONYMOUS_LIKES, one like per IP per window)
+func (h *PromptHandler) LikePrompt(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil && !h.cfg.AnonymousLikes {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
 
+	id, err := h.parseUintParam(c, "id")
 	if err != nil {
 		return c.Status(400).JSON(APIResponse{
 			Status: "error",
@@ -61,38 +825,105 @@ func (h *PromptHandler) GetPromptByID(c *fiber.Ctx) error {
 		})
 	}
 
-	prompt, err := h.promptService.GetPromptByID(id)
+	var result *services.LikeToggleResponse
+	if user == nil {
+		result, err = h.promptService.AnonymousLike(id, c.IP(), hidesUnverified(h.cfg, user))
+	} else {
+		result, err = h.promptService.LikePrompt(id, user.ID, hidesUnverified(h.cfg, user))
+	}
 	if err != nil {
-		return c.Status(404).JSON(APIResponse{
-			Status:  "error",
-			Message: "Prompt not found",
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to like prompt",
 		})
 	}
 
 	return c.Status(200).JSON(APIResponse{
 		Status:  "success",
-		Message: "Prompt fetched successfully",
-		Data:    prompt,
+		Message: "Prompt liked successfully",
+		Data:    result,
 	})
-
 }
 
-func (h *PromptHandler) CreatePrompt(c *fiber.Ctx) error {
+func (h *PromptHandler) GetTranslations(c *fiber.Ctx) error {
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
 
-	var createReq models.PromptCreateRequest
+	translations, err := h.promptService.GetTranslations(id, hidesUnverified(h.cfg, currentUser(c)))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to fetch translations",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Translations fetched successfully",
+		Data:    translations,
+		Count:   listCount(len(translations)),
+	})
+}
 
-	if err := c.BodyParser(&createReq); err != nil {
+// UpsertTranslation writes one locale's translation, for the prompt's author and moderators
+func (h *PromptHandler) UpsertTranslation(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	id, err := h.parseUintParam(c, "id")
+	if err != nil {
 		return c.Status(400).JSON(APIResponse{
-			Status:  "error",
-			Message: "Invalid request body",
-			Error:   err.Error(),
+			Status: "error",
+			Error:  "Invalid prompt ID",
 		})
 	}
 
-	prompt, err := h.promptService.CreatePrompt(&createReq)
+	var translationReq models.PromptTranslationRequest
+
+	if err := parseBody(c, &translationReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
 
+	translation, err := h.promptService.UpsertTranslation(id, c.Params("locale"), &translationReq, user)
 	if err != nil {
-		// Handle validation errors
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		if strings.Contains(err.Error(), "not allowed") {
+			return c.Status(403).JSON(APIResponse{
+				Status: "error",
+				Error:  "Only the author or a moderator can translate this prompt",
+			})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Prompt not found",
+			})
+		}
 		if strings.Contains(err.Error(), "required") ||
 			strings.Contains(err.Error(), "invalid") {
 			return c.Status(400).JSON(APIResponse{
@@ -102,30 +933,86 @@ func (h *PromptHandler) CreatePrompt(c *fiber.Ctx) error {
 		}
 		return c.Status(500).JSON(APIResponse{
 			Status: "error",
-			Error:  "Failed to create prompt",
+			Error:  "Failed to save translation",
 		})
 	}
 
-	return c.Status(201).JSON(APIResponse{
+	return c.Status(200).JSON(APIResponse{
 		Status:  "success",
-		Message: "Prompt created successfully",
-		Data:    prompt,
+		Message: "Translation saved successfully",
+		Data:    translation,
 	})
-
 }
 
-func (h *PromptHandler) DeletePrompt(c *fiber.Ctx) error {
-	// Parse path parameter
-	id, err := h.parseUintParam(c, "id")
+// BulkTag adds/removes tags across many prompts, moderators only
+// Responds with one result per requested id, in request order
+func (h *PromptHandler) BulkTag(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanVerifyPrompts() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can bulk-tag prompts",
+		})
+	}
+
+	var tagReq models.BulkTagRequest
+
+	if err := parseBody(c, &tagReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	results, err := h.promptService.BulkTag(&tagReq)
 	if err != nil {
-		return c.Status(400).JSON(APIResponse{
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
 			Status: "error",
-			Error:  "Invalid prompt ID",
+			Error:  "Failed to bulk-tag prompts",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts tagged successfully",
+		Data:    results,
+		Count:   listCount(len(results)),
+	})
+}
+
+// MergePrompts folds duplicate prompts into a kept one, moderators only
+func (h *PromptHandler) MergePrompts(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanVerifyPrompts() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can merge prompts",
 		})
 	}
 
-	// Call service
-	err = h.promptService.DeletePrompt(id)
+	var mergeReq models.PromptMergeRequest
+
+	if err := parseBody(c, &mergeReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	prompt, err := h.promptService.MergePrompts(&mergeReq)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return c.Status(404).JSON(APIResponse{
@@ -133,38 +1020,230 @@ func (h *PromptHandler) DeletePrompt(c *fiber.Ctx) error {
 				Error:  "Prompt not found",
 			})
 		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
 		return c.Status(500).JSON(APIResponse{
 			Status: "error",
-			Error:  "Failed to delete prompt",
+			Error:  "Failed to merge prompts",
 		})
 	}
 
 	return c.Status(200).JSON(APIResponse{
 		Status:  "success",
-		Message: "Prompt deleted successfully",
+		Message: "Prompts merged successfully",
+		Data:    prompt,
+	})
+}
+
+// GetLanguageStats serves the languages leaderboard, ?sort=views|likes|prompts
+func (h *PromptHandler) GetLanguageStats(c *fiber.Ctx) error {
+	sort := models.LanguageEngagementSort(c.Query("sort"))
+	if sort != "" && !sort.Valid() {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  map[string]string{"sort": "sort must be one of views, likes, prompts"},
+		})
+	}
+
+	stats, err := h.promptService.GetLanguageEngagement(sort)
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Language stats fetched successfully",
+		Data:    stats,
+		Count:   listCount(len(stats)),
+	})
+}
+
+// GetTrendingTags serves the tags most used on recent prompts
+// ?window= is a number of days or hours (7d, 48h), default 7d and at most 90d; ?limit= up to 50
+func (h *PromptHandler) GetTrendingTags(c *fiber.Ctx) error {
+	fieldErrors := map[string]string{}
+
+	window, err := parseWindow(c.Query("window", "7d"))
+	if err != nil {
+		fieldErrors["window"] = err.Error()
+	}
+
+	limit, err := parseIntQuery(c, "limit", models.DefaultTrendingTagsLimit)
+	if err != nil {
+		fieldErrors["limit"] = err.Error()
+	} else if limit > models.MaxTrendingTagsLimit {
+		fieldErrors["limit"] = fmt.Sprintf("limit must be at most %d", models.MaxTrendingTagsLimit)
+	}
+
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	tags, err := h.promptService.GetTrendingTags(window, limit)
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	h.setCacheHeaders(c)
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Trending tags fetched successfully",
+		Data:    tags,
+		Count:   listCount(len(tags)),
 	})
 }
 
-func (h *PromptHandler) parsePromptQuery(c *fiber.Ctx) (models.PromptFilter, int, int, error) {
+// parseWindow reads a look-back window written as days ("7d") or hours ("48h")
+func parseWindow(value string) (time.Duration, error) {
+	const maxWindow = 90 * 24 * time.Hour
+	errInvalid := errors.New("window must be a number of days or hours, e.g. 7d or 48h")
+
+	if len(value) < 2 {
+		return 0, errInvalid
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 1 {
+		return 0, errInvalid
+	}
+
+	var window time.Duration
+	switch value[len(value)-1] {
+	case 'd':
+		window = time.Duration(amount) * 24 * time.Hour
+	case 'h':
+		window = time.Duration(amount) * time.Hour
+	default:
+		return 0, errInvalid
+	}
+
+	if window > maxWindow {
+		return 0, errors.New("window must be at most 90d")
+	}
+	return window, nil
+}
+
+// setCacheHeaders lets CDNs cache public reads for anonymous callers
+// Requests carrying credentials may get per-user data, so those are never stored
+func (h *PromptHandler) setCacheHeaders(c *fiber.Ctx) {
+	c.Vary(fiber.HeaderAuthorization, fiber.HeaderAccept, fiber.HeaderAcceptLanguage)
+
+	if c.Get(fiber.HeaderAuthorization) != "" || currentUser(c) != nil {
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return
+	}
+
+	if h.cfg.PromptCacheMaxAge <= 0 {
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		return
+	}
+
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", h.cfg.PromptCacheMaxAge))
+}
+
+// parsePromptQuery reads the list filters from the query string
+// Returns a field -> message map for every parameter that is present but invalid
+func (h *PromptHandler) parsePromptQuery(c *fiber.Ctx) (models.PromptFilter, models.PaginationParams, map[string]string) {
 	var filter models.PromptFilter
+	fieldErrors := map[string]string{}
 
 	filter.Language = c.Query("language")
 	filter.Category = c.Query("category")
 	filter.Search = c.Query("search")
 
+	filter.Sort = models.PromptSort(c.Query("sort"))
+	if filter.Sort != "" && !filter.Sort.Valid() {
+		fieldErrors["sort"] = "sort must be one of newest, oldest, popular, verified_first, title, quality"
+	}
+
+	filter.Difficulty = models.DifficultyLevel(c.Query("difficulty"))
+	if filter.Difficulty != "" && !filter.Difficulty.Valid() {
+		fieldErrors["difficulty"] = "difficulty must be one of beginner, intermediate, advanced, expert"
+	}
+
 	if verifiedStr := c.Query("is_verified"); verifiedStr != "" {
 		verified, err := strconv.ParseBool(verifiedStr)
 		if err != nil {
-			return filter, 0, 0, err
+			fieldErrors["is_verified"] = "is_verified must be true or false"
+		} else {
+			filter.IsVerified = &verified
+		}
+	}
+
+	if idsStr := c.Query("ids"); idsStr != "" {
+		ids, err := parseIDList(idsStr)
+		if err != nil {
+			fieldErrors["ids"] = err.Error()
+		} else {
+			filter.IDs = ids
+		}
+	}
+
+	if orderedStr := c.Query("ordered"); orderedStr != "" {
+		ordered, err := strconv.ParseBool(orderedStr)
+		if err != nil {
+			fieldErrors["ordered"] = "ordered must be true or false"
+		} else if ordered && c.Query("ids") == "" {
+			fieldErrors["ordered"] = "ordered requires ids"
+		} else {
+			filter.Ordered = ordered
+		}
+	}
+
+	if seed := c.Query("seed"); seed != "" {
+		if len(seed) > models.MaxSeedLength {
+			fieldErrors["seed"] = fmt.Sprintf("seed must be at most %d characters", models.MaxSeedLength)
+		} else if filter.Sort != "" || filter.Ordered {
+			fieldErrors["seed"] = "seed can't be combined with sort or ordered"
+		} else {
+			filter.Seed = seed
 		}
-		filter.IsVerified = &verified
 	}
 
-	page := h.parseIntQuery(c, "page", 1)
-	limit := h.parseIntQuery(c, "limit", 10)
+	pagination := bindPagination(c, fieldErrors)
+
+	return filter, pagination, fieldErrors
+
+}
+
+// parseIDList parses a comma-separated id list, dropping repeats but keeping first-seen order
+func parseIDList(value string) ([]uint, error) {
+	var ids []uint
+	seen := make(map[uint]bool)
 
-	return filter, page, limit, nil
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil || id == 0 {
+			return nil, errors.New("ids must be a comma-separated list of prompt ids")
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
 
+	if len(ids) > 100 {
+		return nil, errors.New("ids accepts at most 100 ids")
+	}
+	return ids, nil
 }
 
 func (h *PromptHandler) parseUintParam(c *fiber.Ctx, param string) (uint, error) {
@@ -180,17 +1259,3 @@ func (h *PromptHandler) parseUintParam(c *fiber.Ctx, param string) (uint, error)
 
 	return uint(value), nil
 }
-
-func (h *PromptHandler) parseIntQuery(c *fiber.Ctx, key string, defaultValue int) int {
-	valueStr := c.Query(key)
-	if valueStr == "" {
-		return defaultValue
-	}
-
-	value, err := strconv.Atoi(valueStr)
-	if err != nil || value < 1 {
-		return defaultValue
-	}
-
-	return value
-}
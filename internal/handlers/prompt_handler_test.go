@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"PromptGallery/internal/testdb"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUpdatePromptRequiresUser(t *testing.T) {
+	cfg := testConfig()
+	app := fiberAppWith(nil, "PUT", "/prompts/:id", NewPromptHandler(nil, cfg).UpdatePrompt)
+
+	resp, err := app.Test(httptest.NewRequest("PUT", "/prompts/1", strings.NewReader(`{"title": "x"}`)))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 401 {
+		t.Errorf("status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestUpdatePrompt(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	author := testdb.SeedUser(t, a.db, "author", nil)
+	stranger := testdb.SeedUser(t, a.db, "stranger", nil)
+	moderator := testdb.SeedUser(t, a.db, "moderator", func(u *models.User) { u.Role = models.RoleModerator })
+
+	prompt := testdb.SeedPrompt(t, a.db, func(p *models.Prompt) {
+		p.AuthorID = &author.ID
+		p.Hints = "Use a map"
+		p.Tags = `["arrays"]`
+	})
+	target := fmt.Sprintf("/prompts/%d", prompt.ID)
+
+	t.Run("missing prompt", func(t *testing.T) {
+		resp := a.send(t, author, "PUT", "/prompts/999999", `{"title": "Three Sum"}`)
+		if resp.status != 404 {
+			t.Errorf("status = %d, want 404", resp.status)
+		}
+	})
+
+	t.Run("invalid difficulty", func(t *testing.T) {
+		resp := a.send(t, author, "PUT", target, `{"difficulty": "legendary"}`)
+		if resp.status != 400 {
+			t.Errorf("status = %d, want 400", resp.status)
+		}
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		resp := a.send(t, stranger, "PUT", target, `{"title": "Hijacked"}`)
+		if resp.status != 403 {
+			t.Errorf("status = %d, want 403", resp.status)
+		}
+	})
+
+	t.Run("only sent fields change", func(t *testing.T) {
+		resp := a.send(t, author, "PUT", target, `{"title": "Three Sum", "difficulty": "intermediate"}`)
+		if resp.status != 200 {
+			t.Fatalf("status = %d (%s), want 200", resp.status, resp.body.Error)
+		}
+
+		var updated services.PromptResponse
+		resp.decode(t, &updated)
+		if updated.Title != "Three Sum" || updated.Difficulty != models.DifficultyIntermediate {
+			t.Errorf("sent fields not applied: %+v", updated)
+		}
+		if updated.Description != prompt.Description || updated.Hints != prompt.Hints ||
+			updated.Language != prompt.Language || updated.ProblemStatement != prompt.ProblemStatement {
+			t.Errorf("fields that weren't sent changed: %+v", updated)
+		}
+	})
+
+	t.Run("content edit unverifies", func(t *testing.T) {
+		now := time.Now()
+		a.db.Model(&models.Prompt{}).Where("id = ?", prompt.ID).Updates(map[string]interface{}{
+			"is_verified": true, "verified_by": moderator.ID, "verified_at": now,
+		})
+		a.db.Model(moderator).Update("prompts_verified", 1)
+
+		// Tags aren't verified content, the prompt stays verified
+		resp := a.send(t, author, "PUT", target, `{"tags": "[\"arrays\", \"maps\"]"}`)
+		var updated services.PromptResponse
+		resp.decode(t, &updated)
+		if resp.status != 200 || !updated.IsVerified {
+			t.Fatalf("tag edit: status %d, verified %v, want 200 and still verified", resp.status, updated.IsVerified)
+		}
+
+		resp = a.send(t, author, "PUT", target, `{"problem_statement": "Return every pair that adds up to target."}`)
+		resp.decode(t, &updated)
+		if resp.status != 200 || updated.IsVerified {
+			t.Fatalf("content edit: status %d, verified %v, want 200 and unverified", resp.status, updated.IsVerified)
+		}
+
+		var stored models.Prompt
+		a.db.First(&stored, prompt.ID)
+		if stored.VerifiedBy != nil || stored.VerifiedAt != nil {
+			t.Errorf("verifier kept after the edit: %v at %v", stored.VerifiedBy, stored.VerifiedAt)
+		}
+		var verifier models.User
+		a.db.First(&verifier, moderator.ID)
+		if verifier.PromptsVerified != 0 {
+			t.Errorf("verifier's prompts_verified = %d, want 0", verifier.PromptsVerified)
+		}
+
+		var audit models.AuditEntry
+		a.db.Where("action = ? AND target_id = ?", models.AuditActionPromptUnverify, prompt.ID).First(&audit)
+		if audit.ActorID != author.ID || audit.Reason != "content edited" {
+			t.Errorf("audit entry = %+v, want the author's edit recorded", audit)
+		}
+	})
+
+	t.Run("moderator may edit", func(t *testing.T) {
+		resp := a.send(t, moderator, "PUT", target, `{"category": "hashing"}`)
+		if resp.status != 200 {
+			t.Errorf("status = %d (%s), want 200", resp.status, resp.body.Error)
+		}
+	})
+}
+
+func TestUpsertByExternalIDRequiresIntegrator(t *testing.T) {
+	handler := NewPromptHandler(nil, testConfig())
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: 401},
+		{name: "contributor", user: &models.User{Role: models.RoleContributor}, wantStatus: 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiberAppWith(tt.user, "PUT", "/prompts/external/:external_id", handler.UpsertByExternalID)
+
+			resp, err := app.Test(httptest.NewRequest("PUT", "/prompts/external/gh-1", strings.NewReader(`{}`)))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUpsertByExternalID(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	integrator := testdb.SeedUser(t, a.db, "sync-bot", func(u *models.User) { u.Role = models.RoleIntegrator })
+
+	body := func(title string) string {
+		return fmt.Sprintf(`{"title": %q, "description": "Find a pair", "language": "go", "difficulty": "beginner",
+			"category": "algorithms", "problem_statement": "Return the indices of the two numbers that add up to target."}`, title)
+	}
+
+	resp := a.send(t, integrator, "PUT", "/prompts/external/gh-1", body("Two Sum"))
+	if resp.status != 201 {
+		t.Fatalf("create: status = %d (%s), want 201", resp.status, resp.body.Error)
+	}
+	var created services.PromptResponse
+	resp.decode(t, &created)
+	if created.ExternalID != "gh-1" {
+		t.Errorf("external_id = %q, want gh-1", created.ExternalID)
+	}
+
+	resp = a.send(t, integrator, "PUT", "/prompts/external/gh-1", body("Two Sum, revised"))
+	if resp.status != 200 {
+		t.Fatalf("update: status = %d (%s), want 200", resp.status, resp.body.Error)
+	}
+	var updated services.PromptResponse
+	resp.decode(t, &updated)
+	if updated.ID != created.ID || updated.Title != "Two Sum, revised" {
+		t.Errorf("update = id %d title %q, want id %d title %q", updated.ID, updated.Title, created.ID, "Two Sum, revised")
+	}
+
+	// Re-syncing a deleted prompt revives it rather than tripping the unique index
+	a.db.Delete(&models.Prompt{}, created.ID)
+	resp = a.send(t, integrator, "PUT", "/prompts/external/gh-1", body("Two Sum"))
+	if resp.status != 200 {
+		t.Fatalf("revive: status = %d (%s), want 200", resp.status, resp.body.Error)
+	}
+
+	var stored int64
+	a.db.Model(&models.Prompt{}).Where("external_id = ?", "gh-1").Count(&stored)
+	if stored != 1 {
+		t.Errorf("%d live prompts with external id gh-1, want 1", stored)
+	}
+}
+
+func TestUnverifiedHiddenBelowThreshold(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinRoleToViewUnverified = string(models.RoleContributor)
+	cfg.AnonymousLikes = true
+	a := newTestApp(t, cfg)
+
+	contributor := testdb.SeedUser(t, a.db, "contributor", nil)
+	prompt := testdb.SeedPrompt(t, a.db, nil)
+	target := fmt.Sprintf("/prompts/%d", prompt.ID)
+
+	listed := func(user *models.User) bool {
+		t.Helper()
+		resp := a.send(t, user, "GET", "/prompts", "")
+		if resp.status != 200 {
+			t.Fatalf("list: status = %d (%s), want 200", resp.status, resp.body.Error)
+		}
+		var page struct {
+			Data []services.PromptResponse `json:"data"`
+		}
+		resp.decode(t, &page)
+		for _, p := range page.Data {
+			if p.ID == prompt.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	routes := []struct{ method, target string }{
+		{"GET", target},
+		{"GET", target + "/translations"},
+		{"POST", target + "/like"},
+		{"POST", target + "/like/toggle"},
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		for _, route := range routes {
+			if resp := a.send(t, nil, route.method, route.target, ""); resp.status != 404 {
+				t.Errorf("%s %s: status = %d, want 404", route.method, route.target, resp.status)
+			}
+		}
+		if listed(nil) {
+			t.Error("unverified prompt listed for an anonymous caller")
+		}
+	})
+
+	t.Run("contributor", func(t *testing.T) {
+		for _, route := range routes {
+			if resp := a.send(t, contributor, route.method, route.target, ""); resp.status != 200 {
+				t.Errorf("%s %s: status = %d (%s), want 200", route.method, route.target, resp.status, resp.body.Error)
+			}
+		}
+		if !listed(contributor) {
+			t.Error("unverified prompt missing from a contributor's list")
+		}
+	})
+}
+
+func TestSetVerificationRequiresModerator(t *testing.T) {
+	handler := NewPromptHandler(nil, testConfig())
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: 401},
+		{name: "contributor", user: &models.User{Role: models.RoleContributor}, wantStatus: 403},
+		{name: "integrator", user: &models.User{Role: models.RoleIntegrator}, wantStatus: 403},
+	}
+
+	for _, tt := range tests {
+		for path, h := range map[string]fiber.Handler{"verify": handler.VerifyPrompt, "unverify": handler.UnverifyPrompt} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				app := fiberAppWith(tt.user, "POST", "/prompts/:id/"+path, h)
+
+				resp, err := app.Test(httptest.NewRequest("POST", "/prompts/1/"+path, nil))
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			})
+		}
+	}
+}
+
+func TestVerifyAndUnverifyPrompt(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	moderator := testdb.SeedUser(t, a.db, "moderator", func(u *models.User) { u.Role = models.RoleModerator })
+	prompt := testdb.SeedPrompt(t, a.db, nil)
+	target := fmt.Sprintf("/prompts/%d", prompt.ID)
+
+	verifiedCount := func() int {
+		t.Helper()
+		var user models.User
+		a.db.First(&user, moderator.ID)
+		return user.PromptsVerified
+	}
+
+	if resp := a.send(t, moderator, "POST", "/prompts/999999/verify", ""); resp.status != 404 {
+		t.Errorf("verify missing prompt: status = %d, want 404", resp.status)
+	}
+
+	// Verifying twice counts the prompt once
+	for i := 0; i < 2; i++ {
+		resp := a.send(t, moderator, "POST", target+"/verify", "")
+		var verified services.PromptResponse
+		resp.decode(t, &verified)
+		if resp.status != 200 || !verified.IsVerified {
+			t.Fatalf("verify %d: status %d, verified %v, want 200 and verified", i+1, resp.status, verified.IsVerified)
+		}
+	}
+	if got := verifiedCount(); got != 1 {
+		t.Errorf("prompts_verified after verify = %d, want 1", got)
+	}
+
+	var stored models.Prompt
+	a.db.First(&stored, prompt.ID)
+	if stored.VerifiedBy == nil || *stored.VerifiedBy != moderator.ID || stored.VerifiedAt == nil {
+		t.Errorf("verified by %v at %v, want moderator %d and a time", stored.VerifiedBy, stored.VerifiedAt, moderator.ID)
+	}
+
+	for _, body := range []string{"", `{}`, `{"reason": "   "}`} {
+		if resp := a.send(t, moderator, "POST", target+"/unverify", body); resp.status != 400 {
+			t.Errorf("unverify with body %q: status = %d, want 400", body, resp.status)
+		}
+	}
+	a.db.First(&stored, prompt.ID)
+	if !stored.IsVerified {
+		t.Fatal("prompt unverified without a reason")
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := a.send(t, moderator, "POST", target+"/unverify", `{"reason": "Reported: the examples are wrong"}`)
+		var unverified services.PromptResponse
+		resp.decode(t, &unverified)
+		if resp.status != 200 || unverified.IsVerified {
+			t.Fatalf("unverify %d: status %d, verified %v, want 200 and unverified", i+1, resp.status, unverified.IsVerified)
+		}
+	}
+	if got := verifiedCount(); got != 0 {
+		t.Errorf("prompts_verified after unverify = %d, want 0", got)
+	}
+
+	// Only the call that changed something is audited
+	var entries []models.AuditEntry
+	a.db.Where("action = ? AND target_id = ?", models.AuditActionPromptUnverify, prompt.ID).Find(&entries)
+	if len(entries) != 1 || entries[0].ActorID != moderator.ID || entries[0].Reason != "Reported: the examples are wrong" {
+		t.Errorf("audit entries = %+v, want one by moderator %d with the reason", entries, moderator.ID)
+	}
+}
+
+func TestServerControlledFieldsIgnored(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	author := testdb.SeedUser(t, a.db, "author", nil)
+	protected := `"is_verified": true, "verified_by": 1, "view_count": 999, "like_count": 999, "author_id": 12345, "id": 777`
+
+	checkStored := func(t *testing.T, id uint, wantAuthor *uint) {
+		t.Helper()
+
+		var stored models.Prompt
+		a.db.First(&stored, id)
+		var counts models.PromptCount
+		a.db.Limit(1).Find(&counts, "prompt_id = ?", id)
+		if stored.IsVerified || stored.VerifiedBy != nil || counts.ViewCount != 0 || counts.LikeCount != 0 {
+			t.Errorf("stored verified %v by %v, %d views, %d likes, want all unset",
+				stored.IsVerified, stored.VerifiedBy, counts.ViewCount, counts.LikeCount)
+		}
+		if (stored.AuthorID == nil) != (wantAuthor == nil) || (wantAuthor != nil && *stored.AuthorID != *wantAuthor) {
+			t.Errorf("stored author_id = %v, want %v", stored.AuthorID, wantAuthor)
+		}
+	}
+
+	var created services.PromptResponse
+	t.Run("create", func(t *testing.T) {
+		resp := a.send(t, nil, "POST", "/prompts", `{"title": "Two Sum", "description": "Find a pair", "language": "go",
+			"category": "algorithms", "problem_statement": "Return the indices of the two numbers that add up to target.", `+protected+`}`)
+		if resp.status != 201 {
+			t.Fatalf("status = %d (%s), want 201", resp.status, resp.body.Error)
+		}
+		resp.decode(t, &created)
+		if created.ID == 777 || created.IsVerified || created.ViewCount != 0 || created.LikeCount != 0 {
+			t.Errorf("response took server-controlled fields: %+v", created)
+		}
+		checkStored(t, created.ID, nil)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		prompt := testdb.SeedPrompt(t, a.db, func(p *models.Prompt) { p.AuthorID = &author.ID })
+
+		resp := a.send(t, author, "PUT", fmt.Sprintf("/prompts/%d", prompt.ID), `{"title": "Three Sum", `+protected+`}`)
+		if resp.status != 200 {
+			t.Fatalf("status = %d (%s), want 200", resp.status, resp.body.Error)
+		}
+		var updated services.PromptResponse
+		resp.decode(t, &updated)
+		if updated.ID != prompt.ID || updated.Title != "Three Sum" || updated.IsVerified || updated.ViewCount != 0 {
+			t.Errorf("update took server-controlled fields: %+v", updated)
+		}
+		checkStored(t, prompt.ID, &author.ID)
+	})
+
+	t.Run("strict bodies reject them", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.StrictJSONBody = true
+		strict := newTestApp(t, cfg)
+
+		resp := strict.send(t, nil, "POST", "/prompts", `{"title": "Two Sum", "is_verified": true}`)
+		if resp.status != 400 || resp.body.Errors["is_verified"] == "" {
+			t.Errorf("status = %d, errors = %v, want 400 naming is_verified", resp.status, resp.body.Errors)
+		}
+	})
+}
+
+func TestGetLanguageStats(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	seed := func(language string, difficulty models.DifficultyLevel, views, likes int) *models.Prompt {
+		prompt := testdb.SeedPrompt(t, a.db, func(p *models.Prompt) {
+			p.Language, p.Difficulty = language, difficulty
+		})
+		testdb.SetCounts(t, a.db, prompt.ID, views, likes)
+		return prompt
+	}
+	seed("go", models.DifficultyBeginner, 10, 4)
+	seed("go", models.DifficultyAdvanced, 5, 3)
+	seed("python", models.DifficultyIntermediate, 30, 1)
+	seed("rust", models.DifficultyExpert, 0, 0)
+	a.db.Delete(seed("python", models.DifficultyExpert, 1000, 1000))
+	testdb.SeedPrompt(t, a.db, func(p *models.Prompt) { p.Language = "rust" }) // never viewed, no counts row
+
+	want := map[string]models.LanguageEngagement{
+		"go":     {Language: "go", PromptCount: 2, TotalViews: 15, TotalLikes: 7, AvgDifficulty: 2},
+		"python": {Language: "python", PromptCount: 1, TotalViews: 30, TotalLikes: 1, AvgDifficulty: 2},
+		"rust":   {Language: "rust", PromptCount: 2, TotalViews: 0, TotalLikes: 0, AvgDifficulty: 2.5},
+	}
+
+	tests := []struct {
+		query     string
+		wantOrder []string
+	}{
+		{query: "", wantOrder: []string{"python", "go", "rust"}},
+		{query: "?sort=likes", wantOrder: []string{"go", "python", "rust"}},
+		{query: "?sort=prompts", wantOrder: []string{"go", "rust", "python"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("sort"+tt.query, func(t *testing.T) {
+			resp := a.send(t, nil, "GET", "/stats/languages"+tt.query, "")
+			if resp.status != 200 {
+				t.Fatalf("status = %d (%s), want 200", resp.status, resp.body.Error)
+			}
+
+			var stats []models.LanguageEngagement
+			resp.decode(t, &stats)
+
+			var order []string
+			for _, stat := range stats {
+				order = append(order, stat.Language)
+				if stat != want[stat.Language] {
+					t.Errorf("%s = %+v, want %+v", stat.Language, stat, want[stat.Language])
+				}
+			}
+			if strings.Join(order, ",") != strings.Join(tt.wantOrder, ",") {
+				t.Errorf("order = %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+
+	if resp := a.send(t, nil, "GET", "/stats/languages?sort=stars", ""); resp.status != 400 {
+		t.Errorf("unknown sort: status = %d, want 400", resp.status)
+	}
+}
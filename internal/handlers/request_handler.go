@@ -1 +1,379 @@
 package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromptRequestHandler struct {
+	requestService *services.PromptRequestService
+	cfg            *config.Config
+}
+
+func NewPromptRequestHandler(requestService *services.PromptRequestService, cfg *config.Config) *PromptRequestHandler {
+	return &PromptRequestHandler{
+		requestService: requestService,
+		cfg:            cfg,
+	}
+}
+
+// CreateRequest takes a public prompt request, no account needed
+func (h *PromptRequestHandler) CreateRequest(c *fiber.Ctx) error {
+	var createReq models.PromptRequestCreateRequest
+
+	if err := parseBody(c, &createReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	request, err := h.requestService.CreateRequest(&createReq)
+	if err != nil {
+		var validationErr *services.ValidationError
+		if errors.As(err, &validationErr) {
+			return c.Status(400).JSON(validationErrorResponse(validationErr))
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to create request",
+		})
+	}
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "Request submitted successfully",
+		Data:    request,
+	})
+}
+
+// GetRequests is the admin request list, filtered by query params and paginated like the prompt list
+func (h *PromptRequestHandler) GetRequests(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageRequests() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can list requests",
+		})
+	}
+
+	fieldErrors := map[string]string{}
+	pagination := bindPagination(c, fieldErrors)
+	filter := parseRequestFilter(c, fieldErrors)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	result, err := h.requestService.GetRequests(filter, pagination)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Requests fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// UpdateRequest lets moderators move a request through its workflow, assign it and annotate it
+// 409 when the status change isn't allowed from the request's current status
+func (h *PromptRequestHandler) UpdateRequest(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageRequests() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can update requests",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid request ID",
+		})
+	}
+
+	var updateReq models.PromptRequestUpdateRequest
+
+	if err := parseBody(c, &updateReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	request, err := h.requestService.UpdateRequest(uint(id), &updateReq, user.ID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Request not found",
+			})
+		case strings.Contains(err.Error(), "not allowed"):
+			return c.Status(409).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		case strings.Contains(err.Error(), "invalid"):
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to update request",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Request updated successfully",
+		Data:    request,
+	})
+}
+
+// MarkDuplicate closes a request as a duplicate of another, moderators only
+// 404 when either request is missing, 409 when the request can no longer be rejected
+func (h *PromptRequestHandler) MarkDuplicate(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageRequests() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can mark duplicate requests",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid request ID",
+		})
+	}
+
+	var duplicateReq models.DuplicateOfRequest
+
+	if err := parseBody(c, &duplicateReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	request, err := h.requestService.MarkDuplicate(uint(id), &duplicateReq)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "canonical request not found"):
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Canonical request not found",
+			})
+		case strings.Contains(err.Error(), "not found"):
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "Request not found",
+			})
+		case strings.Contains(err.Error(), "not allowed"):
+			return c.Status(409).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		case strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid"):
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to mark request as duplicate",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Request marked as duplicate",
+		Data:    request,
+	})
+}
+
+func parseRequestFilter(c *fiber.Ctx, fieldErrors map[string]string) models.RequestFilter {
+	filter := models.RequestFilter{
+		Status:              models.RequestStatus(c.Query("status")),
+		Priority:            models.Priority(c.Query("priority")),
+		RequestedLanguage:   c.Query("language"),
+		RequestedDifficulty: models.DifficultyLevel(c.Query("difficulty")),
+		RequestedCategory:   c.Query("category"),
+		RequesterEmail:      c.Query("requester_email"),
+		Search:              strings.TrimSpace(c.Query("search")),
+	}
+
+	if value := c.Query("is_urgent"); value != "" {
+		urgent, err := strconv.ParseBool(value)
+		if err != nil {
+			fieldErrors["is_urgent"] = "is_urgent must be true or false"
+		} else {
+			filter.IsUrgent = &urgent
+		}
+	}
+
+	if value := c.Query("is_rejected"); value != "" {
+		rejected, err := strconv.ParseBool(value)
+		if err != nil {
+			fieldErrors["is_rejected"] = "is_rejected must be true or false"
+		} else {
+			filter.IsRejected = &rejected
+		}
+	}
+
+	if value := c.Query("assigned_to_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			fieldErrors["assigned_to_id"] = "assigned_to_id must be a user id"
+		} else {
+			assignedTo := uint(id)
+			filter.AssignedToID = &assignedTo
+		}
+	}
+
+	return filter
+}
+
+// GetCompletedRequests serves the public changelog of fulfilled requests, paginated with ?page= and ?limit=
+func (h *PromptRequestHandler) GetCompletedRequests(c *fiber.Ctx) error {
+	fieldErrors := map[string]string{}
+	pagination := bindPagination(c, fieldErrors)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	result, err := h.requestService.GetCompletedRequests(pagination)
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Completed requests fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// GetSourceRequests lists the requests that the prompt in :id fulfilled
+// Admin fields (notes, assignment, requester contact) are only shown to request managers
+func (h *PromptRequestHandler) GetSourceRequests(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid prompt ID",
+		})
+	}
+
+	user := currentUser(c)
+	full := user != nil && user.Role.CanManageRequests()
+
+	requests, count, err := h.requestService.GetSourceRequests(uint(id), full)
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Source requests fetched successfully",
+		Data:    requests,
+		Count:   listCount(count),
+	})
+}
+
+// BulkPriority sets one priority on many requests, moderators and up only
+// Responds with a per-id result, unknown ids are reported as not_found
+func (h *PromptRequestHandler) BulkPriority(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageRequests() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only moderators can reprioritize requests",
+		})
+	}
+
+	var priorityReq models.BulkPriorityRequest
+
+	if err := parseBody(c, &priorityReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	results, err := h.requestService.BulkSetPriority(&priorityReq, user.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to update request priorities",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Request priorities updated successfully",
+		Data:    results,
+		Count:   listCount(len(results)),
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"PromptGallery/internal/testdb"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCompletedRequests(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	prompt := testdb.SeedPrompt(t, a.db, nil)
+
+	completed := func(title string, completedAt time.Time) *models.PromptRequest {
+		at := completedAt.Unix()
+		return testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) {
+			r.RequestedTitle = title
+			r.Status = models.StatusCompleted
+			r.CompletedPromptID = &prompt.ID
+			r.CompletedAt = &at
+		})
+	}
+
+	now := time.Now()
+	completed("Older", now.Add(-48*time.Hour))
+	completed("Newest", now.Add(-time.Hour))
+	completed("Middle", now.Add(-24*time.Hour))
+	testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) { r.RequestedTitle = "Still pending" })
+	testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) {
+		r.RequestedTitle = "Turned down"
+		r.Status = models.StatusRejected
+	})
+
+	resp := a.send(t, nil, "GET", "/requests/completed", "")
+	if resp.status != 200 {
+		t.Fatalf("status = %d (%s), want 200", resp.status, resp.body.Error)
+	}
+
+	var page services.PaginationCompletedRequestResponse
+	resp.decode(t, &page)
+
+	var titles []string
+	for _, request := range page.Data {
+		titles = append(titles, request.RequestedTitle)
+		if request.CompletedPromptID == nil || *request.CompletedPromptID != prompt.ID {
+			t.Errorf("%s links to prompt %v, want %d", request.RequestedTitle, request.CompletedPromptID, prompt.ID)
+		}
+		if request.CompletedAt == "" {
+			t.Errorf("%s has no completed_at", request.RequestedTitle)
+		}
+	}
+	if got := strings.Join(titles, ", "); got != "Newest, Middle, Older" || page.Total != 3 {
+		t.Errorf("completed = %s (total %d), want Newest, Middle, Older (total 3)", got, page.Total)
+	}
+
+	// The public changelog doesn't carry requester contact details
+	if strings.Contains(string(resp.body.Data), "requester_email") {
+		t.Errorf("response leaks requester_email: %s", resp.body.Data)
+	}
+
+	if resp := a.send(t, nil, "GET", "/requests/completed?limit=2&page=2", ""); resp.status == 200 {
+		resp.decode(t, &page)
+		if len(page.Data) != 1 || page.Data[0].RequestedTitle != "Older" {
+			t.Errorf("page 2 = %+v, want just Older", page.Data)
+		}
+	} else {
+		t.Errorf("page 2: status = %d, want 200", resp.status)
+	}
+}
+
+func TestBulkPriorityRequiresManager(t *testing.T) {
+	handler := NewPromptRequestHandler(nil, testConfig())
+
+	tests := []struct {
+		name       string
+		user       *models.User
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: 401},
+		{name: "contributor", user: &models.User{Role: models.RoleContributor}, wantStatus: 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiberAppWith(tt.user, "POST", "/requests/bulk-priority", handler.BulkPriority)
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/requests/bulk-priority", strings.NewReader(`{"ids": [1], "priority": "high"}`)))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBulkPriority(t *testing.T) {
+	a := newTestApp(t, testConfig())
+	moderator := testdb.SeedUser(t, a.db, "moderator", func(u *models.User) { u.Role = models.RoleModerator })
+
+	low := testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) { r.Priority = models.PriorityLow })
+	normal := testdb.SeedRequest(t, a.db, nil)
+	urgent := testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) { r.Priority = models.PriorityUrgent })
+	untouched := testdb.SeedRequest(t, a.db, func(r *models.PromptRequest) { r.Priority = models.PriorityLow })
+
+	t.Run("invalid priority", func(t *testing.T) {
+		resp := a.send(t, moderator, "POST", "/requests/bulk-priority", fmt.Sprintf(`{"ids": [%d], "priority": "whenever"}`, low.ID))
+		if resp.status != 400 {
+			t.Errorf("status = %d, want 400", resp.status)
+		}
+
+		var stored models.PromptRequest
+		a.db.First(&stored, low.ID)
+		if stored.Priority != models.PriorityLow {
+			t.Errorf("priority = %s after a rejected call, want low", stored.Priority)
+		}
+	})
+
+	t.Run("no ids", func(t *testing.T) {
+		if resp := a.send(t, moderator, "POST", "/requests/bulk-priority", `{"ids": [], "priority": "high"}`); resp.status != 400 {
+			t.Errorf("status = %d, want 400", resp.status)
+		}
+	})
+
+	t.Run("every listed request moves", func(t *testing.T) {
+		body := fmt.Sprintf(`{"ids": [%d, 999999, %d, %d], "priority": "urgent"}`, low.ID, normal.ID, urgent.ID)
+		resp := a.send(t, moderator, "POST", "/requests/bulk-priority", body)
+		if resp.status != 200 {
+			t.Fatalf("status = %d (%s), want 200", resp.status, resp.body.Error)
+		}
+
+		var results []services.BulkResult
+		resp.decode(t, &results)
+		want := []services.BulkResult{
+			{ID: low.ID, Status: "updated", Value: "urgent"},
+			{ID: 999999, Status: "not_found"},
+			{ID: normal.ID, Status: "updated", Value: "urgent"},
+			{ID: urgent.ID, Status: "unchanged", Value: "urgent"},
+		}
+		if fmt.Sprint(results) != fmt.Sprint(want) {
+			t.Errorf("results = %+v, want %+v", results, want)
+		}
+
+		for _, request := range []*models.PromptRequest{low, normal, urgent} {
+			var stored models.PromptRequest
+			a.db.First(&stored, request.ID)
+			if stored.Priority != models.PriorityUrgent {
+				t.Errorf("request %d priority = %s, want urgent", request.ID, stored.Priority)
+			}
+		}
+		var stored models.PromptRequest
+		a.db.First(&stored, untouched.ID)
+		if stored.Priority != models.PriorityLow {
+			t.Errorf("unlisted request priority = %s, want low", stored.Priority)
+		}
+	})
+
+	t.Run("changes are audited", func(t *testing.T) {
+		var entries []models.AuditEntry
+		a.db.Where("action = ?", models.AuditActionRequestPriority).Order("target_id").Find(&entries)
+
+		// The already urgent request didn't change, so it has no entry
+		if len(entries) != 2 {
+			t.Fatalf("%d audit entries, want 2: %+v", len(entries), entries)
+		}
+		wantOld := map[uint]string{low.ID: "low", normal.ID: "normal"}
+		for _, entry := range entries {
+			if entry.ActorID != moderator.ID || entry.OldValue != wantOld[entry.TargetID] || entry.NewValue != "urgent" {
+				t.Errorf("audit entry = %+v, want moderator %d moving %d from %s to urgent",
+					entry, moderator.ID, entry.TargetID, wantOld[entry.TargetID])
+			}
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SavedViewHandler serves the current user's saved prompt filters under /me/views
+type SavedViewHandler struct {
+	viewService   *services.SavedViewService
+	promptService *services.PromptService
+	cfg           *config.Config
+}
+
+func NewSavedViewHandler(viewService *services.SavedViewService, promptService *services.PromptService, cfg *config.Config) *SavedViewHandler {
+	return &SavedViewHandler{
+		viewService:   viewService,
+		promptService: promptService,
+		cfg:           cfg,
+	}
+}
+
+func (h *SavedViewHandler) GetViews(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	views, err := h.viewService.ListViews(user.ID)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to fetch views")
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Views fetched successfully",
+		Data:    views,
+		Count:   listCount(len(views)),
+	})
+}
+
+func (h *SavedViewHandler) GetView(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	view, err := h.viewService.GetView(parseViewID(c), user.ID)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to fetch view")
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "View fetched successfully",
+		Data:    view,
+	})
+}
+
+func (h *SavedViewHandler) CreateView(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	var viewReq models.SavedViewRequest
+
+	if err := parseBody(c, &viewReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	view, err := h.viewService.CreateView(user.ID, &viewReq)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to create view")
+	}
+
+	setCreatedLocation(c, view.ID)
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "View created successfully",
+		Data:    view,
+	})
+}
+
+func (h *SavedViewHandler) UpdateView(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	var viewReq models.SavedViewRequest
+
+	if err := parseBody(c, &viewReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	view, err := h.viewService.UpdateView(parseViewID(c), user.ID, &viewReq)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to update view")
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "View updated successfully",
+		Data:    view,
+	})
+}
+
+func (h *SavedViewHandler) DeleteView(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	if err := h.viewService.DeleteView(parseViewID(c), user.ID); err != nil {
+		return h.errorResponse(c, err, "Failed to delete view")
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "View deleted successfully",
+	})
+}
+
+// GetViewPrompts lists prompts matching the saved filter, paginated with ?page= and ?limit=
+func (h *SavedViewHandler) GetViewPrompts(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	fieldErrors := map[string]string{}
+	pagination := bindPagination(c, fieldErrors)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter, err := h.viewService.FilterFor(parseViewID(c), user.ID)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to fetch view")
+	}
+
+	filter.VerifiedOnly = hidesUnverified(h.cfg, user)
+
+	result, err := h.promptService.GetAllPrompts(filter, pagination)
+	if err != nil {
+		return h.errorResponse(c, err, "Failed to fetch prompts")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Prompts fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// parseViewID reads :id, 0 (rejected by the service as invalid) when it isn't a number
+func parseViewID(c *fiber.Ctx) uint {
+	id, _ := strconv.ParseUint(c.Params("id"), 10, 32)
+	return uint(id)
+}
+
+func (h *SavedViewHandler) errorResponse(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return c.Status(404).JSON(APIResponse{
+			Status: "error",
+			Error:  "View not found",
+		})
+	case strings.Contains(err.Error(), "already exists"):
+		return c.Status(409).JSON(APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	case strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "invalid"):
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	}
+	return c.Status(500).JSON(APIResponse{
+		Status: "error",
+		Error:  fallback,
+	})
+}
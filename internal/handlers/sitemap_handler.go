@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"PromptGallery/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSitemap serves /sitemap.xml with every verified prompt
+// Past services.SitemapMaxURLs prompts it becomes a sitemap index of /sitemap-N.xml files
+func (h *PromptHandler) GetSitemap(c *fiber.Ctx) error {
+	pages, err := h.promptService.SitemapPageCount()
+	if err != nil {
+		return c.Status(500).SendString("Failed to build sitemap")
+	}
+
+	var body []byte
+	if pages > 1 {
+		body, err = services.RenderSitemapIndex(c.BaseURL(), pages)
+	} else {
+		body, err = h.promptService.RenderSitemap(h.siteURL(c), 1)
+	}
+	if err != nil {
+		return c.Status(500).SendString("Failed to build sitemap")
+	}
+
+	return sendSitemap(c, body)
+}
+
+// GetSitemapPage serves one /sitemap-N.xml file listed by the sitemap index
+func (h *PromptHandler) GetSitemapPage(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Params("page"))
+	if err != nil || page < 1 {
+		return c.Status(404).SendString("Sitemap not found")
+	}
+
+	body, err := h.promptService.RenderSitemap(h.siteURL(c), page)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).SendString("Sitemap not found")
+		}
+		return c.Status(500).SendString("Failed to build sitemap")
+	}
+
+	return sendSitemap(c, body)
+}
+
+// siteURL is where prompt pages live, SITE_URL or this server when unset
+func (h *PromptHandler) siteURL(c *fiber.Ctx) string {
+	if h.cfg.SiteURL != "" {
+		return h.cfg.SiteURL
+	}
+	return c.BaseURL()
+}
+
+func sendSitemap(c *fiber.Ctx, body []byte) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.Status(200).Send(body)
+}
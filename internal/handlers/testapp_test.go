@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"PromptGallery/internal/services"
+	"PromptGallery/internal/testdb"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// testConfig holds the LoadConfig defaults the handlers and services read
+func testConfig() *config.Config {
+	return &config.Config{
+		Environment:                "test",
+		DefaultPromptSort:          "newest",
+		MinRoleToViewUnverified:    models.RoleAnonymous,
+		AnonymousLikeWindowSeconds: 3600,
+		APITokenTTLHours:           720,
+		LoginMaxFailures:           5,
+		LoginFailureWindowSeconds:  900,
+		MaxDescriptionLength:       5000,
+		MaxProblemStatementLength:  20000,
+		MaxTagsPerPrompt:           10,
+		MinSearchLength:            2,
+		DefaultAuthorName:          "Community",
+		BcryptCost:                 4,
+	}
+}
+
+// testApp serves the prompt, stats, request and user routes over a test database
+type testApp struct {
+	app *fiber.App
+	db  *gorm.DB
+}
+
+// testUserHeader names the user a test request is made as, requests without it are anonymous
+const testUserHeader = "X-Test-User"
+
+func newTestApp(t *testing.T, cfg *config.Config) *testApp {
+	t.Helper()
+
+	db := testdb.Open(t)
+
+	promptRepo := repositories.NewPromptRepository(db, cfg.TitleCollation, cfg.QualityWeights)
+	userRepo := repositories.NewUserRepository(db)
+	requestRepo := repositories.NewPromptRequestRepository(db)
+	promptHandler := NewPromptHandler(services.NewPromptService(promptRepo, userRepo, cfg, nil), cfg)
+	requestHandler := NewPromptRequestHandler(services.NewPromptRequestService(requestRepo), cfg)
+	userHandler := NewUserHandler(services.NewUserService(userRepo, promptRepo, requestRepo, cfg), cfg)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if id := c.Get(testUserHeader); id != "" {
+			var user models.User
+			if err := db.First(&user, id).Error; err != nil {
+				return err
+			}
+			c.Locals("user", &user)
+		}
+		return c.Next()
+	})
+
+	prompts := app.Group("/prompts")
+	prompts.Get("/", promptHandler.GetPrompts)
+	prompts.Post("/", promptHandler.CreatePrompt)
+	prompts.Put("/external/:external_id", promptHandler.UpsertByExternalID)
+	prompts.Get("/:id", promptHandler.GetPromptByID)
+	prompts.Put("/:id", promptHandler.UpdatePrompt)
+	prompts.Post("/:id/like", promptHandler.LikePrompt)
+	prompts.Post("/:id/like/toggle", promptHandler.ToggleLike)
+	prompts.Get("/:id/translations", promptHandler.GetTranslations)
+	prompts.Post("/:id/verify", promptHandler.VerifyPrompt)
+	prompts.Post("/:id/unverify", promptHandler.UnverifyPrompt)
+
+	app.Get("/stats/languages", promptHandler.GetLanguageStats)
+
+	requests := app.Group("/requests")
+	requests.Get("/completed", requestHandler.GetCompletedRequests)
+	requests.Post("/bulk-priority", requestHandler.BulkPriority)
+
+	users := app.Group("/users")
+	users.Get("/", userHandler.GetUsers)
+	users.Post("/", userHandler.CreateUser)
+	users.Get("/:id", userHandler.GetUserByID)
+	users.Patch("/:id", userHandler.UpdateUser)
+
+	return &testApp{app: app, db: db}
+}
+
+// testResponse is an APIResponse with Data left raw for the test to decode
+type testResponse struct {
+	status int
+	body   struct {
+		Status  string            `json:"status"`
+		Message string            `json:"message"`
+		Error   string            `json:"error"`
+		Errors  map[string]string `json:"errors"`
+		Data    json.RawMessage   `json:"data"`
+	}
+}
+
+// send makes a request as user (nil for anonymous) with an optional JSON body
+func (a *testApp) send(t *testing.T, user *models.User, method, target, body string) testResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	if user != nil {
+		req.Header.Set(testUserHeader, fmt.Sprint(user.ID))
+	}
+
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+
+	result := testResponse{status: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(&result.body); err != nil {
+		t.Fatalf("%s %s: decode response: %v", method, target, err)
+	}
+	return result
+}
+
+// decode unmarshals the response's data into v
+func (r testResponse) decode(t *testing.T, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.body.Data, v); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+}
+
+// fiberAppWith serves a single handler to user (nil for anonymous) without a database,
+// for checks made before the handler calls its service
+func fiberAppWith(user *models.User, method, path string, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Add(method, path, func(c *fiber.Ctx) error {
+		if user != nil {
+			c.Locals("user", user)
+		}
+		return handler(c)
+	})
+	return app
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+)
+
+type UserHandler struct {
+	userService *services.UserService
+	cfg         *config.Config
+}
+
+func NewUserHandler(userService *services.UserService, cfg *config.Config) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		cfg:         cfg,
+	}
+}
+
+// CreateUser registers an account; anonymous callers sign themselves up as contributors
+// 409 when the email or username is taken, 403 for a role or email domain the caller may not use
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+	var createReq models.UserCreateRequest
+
+	if err := parseBody(c, &createReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	user, err := h.userService.CreateUser(&createReq, currentUser(c))
+	if err != nil {
+		return userWriteErrorResponse(c, err, "Failed to create user")
+	}
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "User created successfully",
+		Data:    user,
+	})
+}
+
+func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid user ID",
+		})
+	}
+
+	profile, err := h.userService.GetUser(uint(id), currentUser(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status:  "error",
+				Message: "User not found",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to fetch user",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "User fetched successfully",
+		Data:    profile,
+	})
+}
+
+// GetUsers lists users, filtered by ?role= and ?search= and paginated like the prompt list
+func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
+	fieldErrors := map[string]string{}
+	pagination := bindPagination(c, fieldErrors)
+	if len(fieldErrors) > 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status:  "error",
+			Message: "Invalid query parameters",
+			Errors:  fieldErrors,
+		})
+	}
+
+	filter := models.UserFilter{
+		Role:   models.UserRole(c.Query("role")),
+		Search: strings.TrimSpace(c.Query("search")),
+	}
+
+	result, err := h.userService.GetUsers(filter, pagination, currentUser(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status:  "error",
+				Message: "Invalid query parameters",
+				Error:   err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status:  "error",
+			Message: "Internal server error",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Users fetched successfully",
+		Data:    result,
+		Count:   listCount(len(result.Data)),
+	})
+}
+
+// UpdateUser patches a profile, allowed for the user themselves and admins
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return c.Status(400).JSON(APIResponse{
+			Status: "error",
+			Error:  "Invalid user ID",
+		})
+	}
+	if user.ID != uint(id) && !user.Role.CanManageUsers() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "You can only update your own profile",
+		})
+	}
+
+	var updateReq models.UserUpdateRequest
+
+	if err := parseBody(c, &updateReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	updated, err := h.userService.UpdateUser(uint(id), &updateReq, user)
+	if err != nil {
+		return userWriteErrorResponse(c, err, "Failed to update user")
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "User updated successfully",
+		Data:    updated,
+	})
+}
+
+// userWriteErrorResponse maps CreateUser/UpdateUser errors to a status
+func userWriteErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return c.Status(400).JSON(validationErrorResponse(validationErr))
+	}
+
+	switch {
+	case strings.Contains(err.Error(), "already exists"):
+		return c.Status(409).JSON(APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	case strings.Contains(err.Error(), "not allowed"):
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  err.Error(),
+		})
+	case strings.Contains(err.Error(), "not found"):
+		return c.Status(404).JSON(APIResponse{
+			Status: "error",
+			Error:  "User not found",
+		})
+	}
+	return c.Status(500).JSON(APIResponse{
+		Status: "error",
+		Error:  fallback,
+	})
+}
+
+func (h *UserHandler) GetUserByUsername(c *fiber.Ctx) error {
+	profile, err := h.userService.GetProfileByUsername(c.Params("username"), currentUser(c))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  "Invalid username",
+			})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status:  "error",
+				Message: "User not found",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to fetch user",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "User fetched successfully",
+		Data:    profile,
+	})
+}
+
+// GetMySummary returns the authenticated user's contribution dashboard
+func (h *UserHandler) GetMySummary(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	summary, err := h.userService.GetSummary(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to fetch summary",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Summary fetched successfully",
+		Data:    summary,
+	})
+}
+
+// RecomputeStats recounts the user statistics counters, admins only
+func (h *UserHandler) RecomputeStats(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageUsers() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only admins can recompute user stats",
+		})
+	}
+
+	updated, err := h.userService.RecomputeStats()
+	if err != nil {
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to recompute user stats",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "User stats recomputed successfully",
+		Data:    fiber.Map{"updated": updated},
+	})
+}
+
+// MergeUsers folds a duplicate account into another, admins only
+func (h *UserHandler) MergeUsers(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+	if !user.Role.CanManageUsers() {
+		return c.Status(403).JSON(APIResponse{
+			Status: "error",
+			Error:  "Only admins can merge users",
+		})
+	}
+
+	var mergeReq models.UserMergeRequest
+
+	if err := parseBody(c, &mergeReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	kept, err := h.userService.MergeUsers(&mergeReq)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(APIResponse{
+				Status: "error",
+				Error:  "User not found",
+			})
+		}
+		if strings.Contains(err.Error(), "required") ||
+			strings.Contains(err.Error(), "invalid") {
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to merge users",
+		})
+	}
+
+	return c.Status(200).JSON(APIResponse{
+		Status:  "success",
+		Message: "Users merged successfully",
+		Data:    kept,
+	})
+}
+
+// CreateToken signs in with a login (email or username) and password and returns a new bearer token
+// Wrong credentials are a 401 whether or not the login exists; too many failures from one IP a 429
+func (h *UserHandler) CreateToken(c *fiber.Ctx) error {
+	var tokenReq models.TokenCreateRequest
+
+	if err := parseBody(c, &tokenReq, h.cfg.StrictJSONBody); err != nil {
+		return c.Status(400).JSON(bodyErrorResponse(err))
+	}
+
+	token, err := h.userService.IssueToken(&tokenReq, c.IP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			return c.Status(401).JSON(APIResponse{
+				Status: "error",
+				Error:  "Invalid credentials",
+			})
+		case errors.Is(err, services.ErrTooManyAttempts):
+			return c.Status(429).JSON(APIResponse{
+				Status: "error",
+				Error:  "Too many failed sign-in attempts, try again later",
+			})
+		case strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "invalid"):
+			return c.Status(400).JSON(APIResponse{
+				Status: "error",
+				Error:  err.Error(),
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to sign in",
+		})
+	}
+
+	return c.Status(201).JSON(APIResponse{
+		Status:  "success",
+		Message: "Token issued successfully",
+		Data:    token,
+	})
+}
+
+// RevokeToken signs out by deleting the bearer token the request was made with
+func (h *UserHandler) RevokeToken(c *fiber.Ctx) error {
+	token, _ := c.Locals("token").(string)
+	if token == "" {
+		return c.Status(401).JSON(APIResponse{
+			Status: "error",
+			Error:  "Authentication required",
+		})
+	}
+
+	if err := h.userService.RevokeToken(token); err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			return c.Status(401).JSON(APIResponse{
+				Status: "error",
+				Error:  "Invalid or expired token",
+			})
+		}
+		return c.Status(500).JSON(APIResponse{
+			Status: "error",
+			Error:  "Failed to revoke token",
+		})
+	}
+
+	return c.SendStatus(204)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/testdb"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUserWriteErrorResponse(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{err: errors.New("email domain not allowed: gmail.com"), wantStatus: 403},
+		{err: errors.New("role not allowed: only admins can set a role"), wantStatus: 403},
+		{err: errors.New("username already exists"), wantStatus: 409},
+		{err: errors.New("user not found"), wantStatus: 404},
+		{err: errors.New("connection reset"), wantStatus: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				return userWriteErrorResponse(c, tt.err, "Failed to create user")
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUserEndpoints(t *testing.T) {
+	a := newTestApp(t, testConfig())
+
+	var ann models.UserResponse
+	t.Run("create", func(t *testing.T) {
+		resp := a.send(t, nil, "POST", "/users", `{"name": "Ann", "email": "Ann@Example.com", "username": "ann", "password": "correct horse"}`)
+		if resp.status != 201 {
+			t.Fatalf("status = %d (%s %v), want 201", resp.status, resp.body.Error, resp.body.Errors)
+		}
+		resp.decode(t, &ann)
+		if ann.ID == 0 || ann.Email != "ann@example.com" || ann.Role != models.RoleContributor || !ann.IsActive {
+			t.Errorf("created = %+v, want an active contributor with a normalized email", ann)
+		}
+		if strings.Contains(string(resp.body.Data), "password") {
+			t.Errorf("response carries the password: %s", resp.body.Data)
+		}
+	})
+
+	t.Run("duplicates conflict", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"email":    `{"name": "Ann", "email": "ANN@example.com", "username": "ann2", "password": "correct horse"}`,
+			"username": `{"name": "Ann", "email": "ann2@example.com", "username": "ann", "password": "correct horse"}`,
+		} {
+			if resp := a.send(t, nil, "POST", "/users", body); resp.status != 409 {
+				t.Errorf("duplicate %s: status = %d, want 409", name, resp.status)
+			}
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp := a.send(t, nil, "GET", fmt.Sprintf("/users/%d", ann.ID), "")
+		if resp.status != 200 {
+			t.Fatalf("status = %d, want 200", resp.status)
+		}
+		var profile models.PublicUserResponse
+		resp.decode(t, &profile)
+		if profile.Username != "ann" || strings.Contains(string(resp.body.Data), "email") {
+			t.Errorf("anonymous profile = %s, want ann without an email", resp.body.Data)
+		}
+
+		if resp := a.send(t, nil, "GET", "/users/999999", ""); resp.status != 404 {
+			t.Errorf("missing user: status = %d, want 404", resp.status)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		bob := testdb.SeedUser(t, a.db, "bob", nil)
+		gone := testdb.SeedUser(t, a.db, "gone", nil)
+		a.db.Model(gone).Update("is_active", false)
+
+		resp := a.send(t, nil, "GET", "/users", "")
+		if resp.status != 200 {
+			t.Fatalf("status = %d, want 200", resp.status)
+		}
+		var page struct {
+			Data  []models.PublicUserResponse `json:"data"`
+			Total int64                       `json:"total"`
+		}
+		resp.decode(t, &page)
+
+		var usernames []string
+		for _, user := range page.Data {
+			usernames = append(usernames, user.Username)
+		}
+		if page.Total != 2 || !slices.Contains(usernames, "ann") || !slices.Contains(usernames, bob.Username) {
+			t.Errorf("listed %v (total %d), want ann and bob but not the inactive user", usernames, page.Total)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		self := &models.User{Model: models.Model{ID: ann.ID}}
+		target := fmt.Sprintf("/users/%d", ann.ID)
+
+		if resp := a.send(t, nil, "PATCH", target, `{"bio": "hi"}`); resp.status != 401 {
+			t.Errorf("anonymous: status = %d, want 401", resp.status)
+		}
+		stranger := testdb.SeedUser(t, a.db, "stranger", nil)
+		if resp := a.send(t, stranger, "PATCH", target, `{"bio": "hi"}`); resp.status != 403 {
+			t.Errorf("another user: status = %d, want 403", resp.status)
+		}
+		if resp := a.send(t, self, "PATCH", target, `{"username": "bob"}`); resp.status != 409 {
+			t.Errorf("taken username: status = %d, want 409", resp.status)
+		}
+
+		resp := a.send(t, self, "PATCH", target, `{"bio": "Gopher", "specialties": ["go", "rust"]}`)
+		if resp.status != 200 {
+			t.Fatalf("status = %d (%s %v), want 200", resp.status, resp.body.Error, resp.body.Errors)
+		}
+		var updated models.UserResponse
+		resp.decode(t, &updated)
+		if updated.Bio != "Gopher" || strings.Join(updated.Specialties, ",") != "go,rust" || updated.Name != "Ann" {
+			t.Errorf("updated = %+v, want the bio and specialties changed and the name kept", updated)
+		}
+
+		var stored models.User
+		a.db.First(&stored, ann.ID)
+		if stored.Specialties != `["go","rust"]` {
+			t.Errorf("stored specialties = %q, want a JSON array", stored.Specialties)
+		}
+	})
+}
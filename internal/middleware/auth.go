@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authenticate resolves an "Authorization: Bearer <token>" header to its user and stores it under
+// the "user" local that handlers read through currentUser, and the raw token under "token"
+// Requests without a bearer token stay anonymous. A bearer token that doesn't resolve is a 401
+// rather than a silent downgrade, so a client with an expired token doesn't get public data back
+func Authenticate(userForToken func(token string) (*models.User, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scheme, token, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return c.Next()
+		}
+
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return invalidToken(c)
+		}
+
+		user, err := userForToken(token)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidToken) {
+				return invalidToken(c)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to authenticate",
+			})
+		}
+
+		c.Locals("user", user)
+		c.Locals("token", token)
+		return c.Next()
+	}
+}
+
+func invalidToken(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="PromptGallery", error="invalid_token"`)
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Invalid or expired token",
+	})
+}
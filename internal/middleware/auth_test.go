@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/services"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAuthenticate(t *testing.T) {
+	userForToken := func(token string) (*models.User, error) {
+		switch token {
+		case "pg_good":
+			return &models.User{Username: "ann"}, nil
+		case "pg_broken":
+			return nil, errors.New("connection refused")
+		}
+		return nil, services.ErrInvalidToken
+	}
+
+	app := fiber.New()
+	app.Use(Authenticate(userForToken))
+	app.Get("/", func(c *fiber.Ctx) error {
+		if user, ok := c.Locals("user").(*models.User); ok {
+			return c.SendString(user.Username + " " + c.Locals("token").(string))
+		}
+		return c.SendString("anonymous")
+	})
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "no header", wantStatus: 200, wantBody: "anonymous"},
+		{name: "valid token", header: "Bearer pg_good", wantStatus: 200, wantBody: "ann pg_good"},
+		{name: "lowercase scheme", header: "bearer pg_good", wantStatus: 200, wantBody: "ann pg_good"},
+		{name: "other scheme stays anonymous", header: "Basic YW5uOnBhc3M=", wantStatus: 200, wantBody: "anonymous"},
+		{name: "unknown token", header: "Bearer pg_nope", wantStatus: 401},
+		{name: "empty token", header: "Bearer ", wantStatus: 401},
+		{name: "lookup failure", header: "Bearer pg_broken", wantStatus: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == 401 && resp.Header.Get(fiber.HeaderWWWAuthenticate) == "" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %q, want %q", body, tt.wantBody)
+				}
+			}
+		})
+	}
+}
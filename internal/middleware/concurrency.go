@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConcurrencyLimit lets at most limit requests run the wrapped routes at once
+// Extra requests are turned away with 429 instead of queueing on the shared DB pool
+// Use one instance across all the routes that should share the slots
+func ConcurrencyLimit(limit int) fiber.Handler {
+	slots := make(chan struct{}, max(limit, 1))
+
+	return func(c *fiber.Ctx) error {
+		select {
+		case slots <- struct{}{}:
+		default:
+			c.Set(fiber.HeaderRetryAfter, "5")
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Too many expensive operations in progress, try again shortly",
+			})
+		}
+		defer func() { <-slots }()
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	app := fiber.New()
+	limit := ConcurrencyLimit(1)
+	app.Get("/slow", limit, func(c *fiber.Ctx) error {
+		entered <- struct{}{}
+		<-release
+		return c.SendStatus(200)
+	})
+	app.Get("/fast", limit, func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	slowDone := make(chan int)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest("GET", "/slow", nil), -1)
+		if err != nil {
+			t.Errorf("slow request failed: %v", err)
+			slowDone <- 0
+			return
+		}
+		slowDone <- resp.StatusCode
+	}()
+	<-entered // the only slot is taken
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/fast", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Errorf("status while saturated = %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Error("429 without Retry-After")
+	}
+
+	close(release)
+	if status := <-slowDone; status != 200 {
+		t.Errorf("slow request status = %d, want 200", status)
+	}
+
+	// The slot is given back once the slow request finishes
+	resp, err = app.Test(httptest.NewRequest("GET", "/fast", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("status after release = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestConcurrencyLimitTreatsZeroAsOne(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", ConcurrencyLimit(0), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// accessLogEntry is one JSON access log line
+type accessLogEntry struct {
+	Time      string  `json:"time"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	RequestID string  `json:"request_id,omitempty"`
+	IP        string  `json:"ip"`
+}
+
+// JSONLogger writes one JSON object per request to out, for log aggregators (ELK, Loki)
+// request_id is read from the "requestid" local, so register the requestid middleware first
+func JSONLogger(out io.Writer) fiber.Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(out)
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		chainErr := c.Next()
+
+		// Let the error handler set the final status before it's logged, like fiber's logger does
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		requestID, _ := c.Locals("requestid").(string)
+		entry := accessLogEntry{
+			Time:      start.UTC().Format(time.RFC3339),
+			Method:    c.Method(),
+			Path:      c.Path(),
+			Status:    c.Response().StatusCode(),
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			RequestID: requestID,
+			IP:        c.IP(),
+		}
+
+		mu.Lock()
+		_ = encoder.Encode(entry)
+		mu.Unlock()
+
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var out bytes.Buffer
+
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Use(JSONLogger(&out))
+	app.Get("/prompts", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+	})
+
+	for _, target := range []string{"/prompts?page=2", "/broken"} {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set(fiber.HeaderXRequestID, "req-"+target[1:3])
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("%s: request failed: %v", target, err)
+		}
+	}
+
+	var entries []accessLogEntry
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var entry accessLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("%d log lines, want one per request", len(entries))
+	}
+
+	first := entries[0]
+	if first.Method != "GET" || first.Path != "/prompts" || first.Status != 200 || first.RequestID != "req-pr" || first.IP == "" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if _, err := time.Parse(time.RFC3339, first.Time); err != nil {
+		t.Errorf("time %q is not RFC 3339: %v", first.Time, err)
+	}
+	if first.LatencyMs < 0 {
+		t.Errorf("latency_ms = %v, want >= 0", first.LatencyMs)
+	}
+
+	// The status the error handler settles on is logged, not the 200 fiber starts with
+	if second := entries[1]; second.Status != fiber.StatusServiceUnavailable || second.RequestID != "req-br" {
+		t.Errorf("second entry = %+v, want status 503 and request id req-br", second)
+	}
+}
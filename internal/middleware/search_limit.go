@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// SearchRateLimit allows each IP at most limit requests carrying ?search= per window
+// Requests without a search term pass untouched and aren't counted, limit <= 0 disables it
+func SearchRateLimit(limit int, window time.Duration) fiber.Handler {
+	if limit <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        limit,
+		Expiration: window,
+		Next: func(c *fiber.Ctx) bool {
+			return strings.TrimSpace(c.Query("search")) == ""
+		},
+		// limiter sets X-RateLimit-Limit/Remaining/Reset on allowed requests but not on the 429,
+		// where it only sets Retry-After (the seconds left in the window), so mirror them here
+		LimitReached: func(c *fiber.Ctx) error {
+			c.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			c.Set("X-RateLimit-Remaining", "0")
+			c.Set("X-RateLimit-Reset", c.GetRespHeader(fiber.HeaderRetryAfter))
+
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Too many searches, try again shortly",
+			})
+		},
+	})
+}
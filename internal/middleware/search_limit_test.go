@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newSearchApp(limit int) *fiber.App {
+	app := fiber.New()
+	app.Use(SearchRateLimit(limit, time.Minute))
+	app.Get("/prompts", func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+	return app
+}
+
+func get(t *testing.T, app *fiber.App, target string) int {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestSearchRateLimit(t *testing.T) {
+	app := newSearchApp(2)
+
+	for i := 0; i < 2; i++ {
+		if status := get(t, app, "/prompts?search=sum"); status != 200 {
+			t.Fatalf("search %d: status = %d, want 200", i+1, status)
+		}
+	}
+	if status := get(t, app, "/prompts?search=sum"); status != fiber.StatusTooManyRequests {
+		t.Errorf("search past the limit: status = %d, want 429", status)
+	}
+
+	// Plain listing isn't counted or limited
+	for i := 0; i < 5; i++ {
+		if status := get(t, app, "/prompts"); status != 200 {
+			t.Fatalf("listing %d: status = %d, want 200", i+1, status)
+		}
+	}
+	if status := get(t, app, "/prompts?search=%20%20"); status != 200 {
+		t.Errorf("blank search: status = %d, want 200", status)
+	}
+}
+
+func TestSearchRateLimitDisabled(t *testing.T) {
+	app := newSearchApp(0)
+
+	for i := 0; i < 10; i++ {
+		if status := get(t, app, "/prompts?search=sum"); status != 200 {
+			t.Fatalf("search %d: status = %d, want 200 with the limit disabled", i+1, status)
+		}
+	}
+}
+
+func TestSearchRateLimitHeadersOn429(t *testing.T) {
+	app := newSearchApp(2)
+
+	for i, want := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/prompts?search=sum", nil))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("search %d: status = %d, want 200", i+1, resp.StatusCode)
+		}
+		if got := resp.Header.Get("X-RateLimit-Remaining"); got != want {
+			t.Errorf("search %d: X-RateLimit-Remaining = %q, want %s", i+1, got, want)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/prompts?search=sum", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", resp.StatusCode)
+	}
+
+	if got := resp.Header.Get("X-RateLimit-Limit"); got != "2" {
+		t.Errorf("X-RateLimit-Limit = %q, want 2", got)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+	reset := resp.Header.Get("X-RateLimit-Reset")
+	if reset == "" || reset != resp.Header.Get(fiber.HeaderRetryAfter) {
+		t.Errorf("X-RateLimit-Reset = %q, want it to match Retry-After %q", reset, resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Largest integer a float64 (and therefore a JavaScript number) holds exactly
+const maxSafeInteger = 1<<53 - 1
+
+// StringIDs re-encodes JSON responses so ids ("id", "*_id", lists under "ids" and the
+// like) and integers beyond 2^53 are sent as strings. JS clients lose precision on such
+// numbers otherwise. Applies to every response when always is true, or per request when
+// the client sends "Accept: application/json; ids=string"
+func StringIDs(always bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		wantsStrings := always || strings.Contains(c.Get(fiber.HeaderAccept), "ids=string")
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if !wantsStrings || !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		encoded, err := stringifyIDs(c.Response().Body())
+		if err != nil {
+			// Not something we can rewrite, send it untouched
+			return nil
+		}
+
+		c.Response().SetBodyRaw(encoded)
+		return nil
+	}
+}
+
+// idFrame is an object or array stringifyIDs is inside of
+type idFrame struct {
+	object  bool
+	key     string // member being written, or for arrays the key the array sits under
+	wantKey bool   // objects only, the next token is a member name
+	empty   bool   // nothing written yet, so no comma before the next item
+}
+
+// stringifyIDs copies body token by token, quoting the numbers StringIDs is after
+// Streaming the tokens keeps object members in the order the handler wrote them
+func stringifyIDs(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	var stack []idFrame
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if len(stack) > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var top *idFrame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			out.WriteByte(byte(delim))
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				stack[len(stack)-1].wantKey = true
+			}
+			continue
+		}
+
+		if top != nil && (top.wantKey || !top.object) && !top.empty {
+			out.WriteByte(',')
+		}
+
+		if top != nil && top.wantKey {
+			name, _ := json.Marshal(token)
+			out.Write(name)
+			out.WriteByte(':')
+			top.key, top.wantKey, top.empty = token.(string), false, false
+			continue
+		}
+
+		var key string
+		if top != nil {
+			key = top.key
+			top.empty = false
+		}
+
+		switch v := token.(type) {
+		case json.Delim:
+			out.WriteByte(byte(v))
+			stack = append(stack, idFrame{object: v == '{', key: key, wantKey: v == '{', empty: true})
+			continue
+		case json.Number:
+			if isIDKey(key) || isUnsafeInteger(v) {
+				quoted, _ := json.Marshal(v.String())
+				out.Write(quoted)
+			} else {
+				out.WriteString(v.String())
+			}
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(encoded)
+		}
+
+		if top != nil && top.object {
+			top.wantKey = true
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+func isIDKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == "id" || key == "ids" || key == "verified_by" ||
+		strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_ids")
+}
+
+func isUnsafeInteger(n json.Number) bool {
+	i, err := n.Int64()
+	if err != nil {
+		// Too large for int64 or not an integer at all
+		return !strings.ContainsAny(n.String(), ".eE")
+	}
+	return i > maxSafeInteger || i < -maxSafeInteger
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStringIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		always bool
+		accept string
+		body   string
+		want   string
+	}{
+		{
+			name: "off by default",
+			body: `{"id":1,"author_id":2}`,
+			want: `{"id":1,"author_id":2}`,
+		},
+		{
+			name:   "requested through Accept",
+			accept: "application/json; ids=string",
+			body:   `{"id":1,"title":"Two Sum"}`,
+			want:   `{"id":"1","title":"Two Sum"}`,
+		},
+		{
+			name:   "nested ids keep member order",
+			always: true,
+			body:   `{"status":"success","data":{"title":"Two Sum","id":7,"author":{"user_id":3,"name":"Ann"},"view_count":12,"verified_by":4}}`,
+			want:   `{"status":"success","data":{"title":"Two Sum","id":"7","author":{"user_id":"3","name":"Ann"},"view_count":12,"verified_by":"4"}}`,
+		},
+		{
+			name:   "id arrays",
+			always: true,
+			body:   `{"ids":[1,2,3],"prompt_ids":[4],"counts":[5,6],"data":[{"id":8,"like_count":0},{"id":9}]}`,
+			want:   `{"ids":["1","2","3"],"prompt_ids":["4"],"counts":[5,6],"data":[{"id":"8","like_count":0},{"id":"9"}]}`,
+		},
+		{
+			name:   "integers beyond 2^53",
+			always: true,
+			body:   `{"total":9007199254740993,"max":9007199254740991,"huge":123456789012345678901234,"ratio":1.5e300,"min":-9007199254740993}`,
+			want:   `{"total":"9007199254740993","max":9007199254740991,"huge":"123456789012345678901234","ratio":1.5e300,"min":"-9007199254740993"}`,
+		},
+		{
+			name:   "other values untouched",
+			always: true,
+			body:   `{"id":null,"tags":[],"meta":{},"ok":true,"note":"a \"quoted\" word"}`,
+			want:   `{"id":null,"tags":[],"meta":{},"ok":true,"note":"a \"quoted\" word"}`,
+		},
+		{
+			name:   "top-level array",
+			always: true,
+			body:   `[{"id":1},{"id":2}]`,
+			want:   `[{"id":"1"},{"id":"2"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(StringIDs(tt.always))
+			app.Get("/", func(c *fiber.Ctx) error {
+				c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+				return c.SendString(tt.body)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.want {
+				t.Errorf("body = %s\nwant   %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringIDsLeavesOtherResponsesAlone(t *testing.T) {
+	app := fiber.New()
+	app.Use(StringIDs(true))
+	app.Get("/text", func(c *fiber.Ctx) error {
+		return c.SendString(`{"id":1}`)
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(`{"id":1`)
+	})
+
+	for path, want := range map[string]string{"/text": `{"id":1}`, "/broken": `{"id":1`} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != want {
+			t.Errorf("%s: body = %s, want %s", path, body, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteBudget logs a warning for requests slower than their route's time budget
+// budgets is keyed by "METHOD /route/:template" or just "/route/:template",
+// routes without an entry use defaultBudget. A zero budget disables the check
+func RouteBudget(defaultBudget time.Duration, budgets map[string]time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		elapsed := time.Since(start)
+
+		// Route() is only the matched route once the chain has run
+		route := c.Route().Path
+
+		budget, ok := budgets[c.Method()+" "+route]
+		if !ok {
+			budget, ok = budgets[route]
+		}
+		if !ok {
+			budget = defaultBudget
+		}
+
+		if budget > 0 && elapsed > budget {
+			log.Printf("WARN slow request: %s %s took %s (budget %s)", c.Method(), route, elapsed.Round(time.Millisecond), budget)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRouteBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback time.Duration
+		budgets  map[string]time.Duration
+		method   string
+		wantWarn bool
+	}{
+		{name: "default exceeded", fallback: time.Millisecond, method: "GET", wantWarn: true},
+		{name: "default met", fallback: time.Hour, method: "GET"},
+		{name: "zero disables", method: "GET"},
+		{name: "route budget wins over default", fallback: time.Millisecond,
+			budgets: map[string]time.Duration{"/prompts/:id": time.Hour}, method: "GET"},
+		{name: "method budget wins over route", fallback: time.Hour,
+			budgets: map[string]time.Duration{"/prompts/:id": time.Hour, "POST /prompts/:id": time.Millisecond}, method: "POST", wantWarn: true},
+		{name: "other method uses route budget", fallback: time.Millisecond,
+			budgets: map[string]time.Duration{"/prompts/:id": time.Hour, "POST /prompts/:id": time.Millisecond}, method: "GET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			log.SetOutput(&logs)
+			defer log.SetOutput(os.Stderr)
+
+			app := fiber.New()
+			app.Use(RouteBudget(tt.fallback, tt.budgets))
+			app.Add(tt.method, "/prompts/:id", func(c *fiber.Ctx) error {
+				time.Sleep(5 * time.Millisecond)
+				return c.SendStatus(200)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(tt.method, "/prompts/42", nil), -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != 200 {
+				t.Errorf("status = %d, want 200", resp.StatusCode)
+			}
+
+			warned := strings.Contains(logs.String(), "slow request: "+tt.method+" /prompts/:id")
+			if warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v (log: %q)", warned, tt.wantWarn, logs.String())
+			}
+		})
+	}
+}
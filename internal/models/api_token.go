@@ -0,0 +1,36 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// APIToken is a bearer token a user signed in for
+// Only the SHA-256 of the token is stored, so a leaked table can't be replayed
+type APIToken struct {
+	Model
+
+	UserID    uint      `gorm:"not null;index" json:"-"`
+	Name      string    `gorm:"size:100" json:"name,omitempty"` // Which client it was issued to, e.g. "cli"
+	TokenHash string    `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName specifies the table name for GORM
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// HashToken is the stored form of a raw bearer token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenCreateRequest signs in with an email or username and password for a new API token
+type TokenCreateRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	Name     string `json:"name,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// AuditEntry records one change a moderator made, who did it and what the value was before and after
+// Entries are only ever inserted, never edited
+type AuditEntry struct {
+	Model
+
+	ActorID  uint   `gorm:"not null;index" json:"actor_id"`
+	Action   string `gorm:"not null;size:50;index" json:"action"` // e.g. "request.priority"
+	TargetID uint   `gorm:"not null;index" json:"target_id"`
+	OldValue string `gorm:"size:200" json:"old_value"`
+	NewValue string `gorm:"size:200" json:"new_value"`
+	Reason   string `gorm:"size:500" json:"reason,omitempty"` // Why, for actions that ask for one
+}
+
+// TableName specifies the table name for GORM
+func (AuditEntry) TableName() string {
+	return "audit_entries"
+}
+
+const (
+	// AuditActionRequestPriority is logged for every request a bulk priority change moves
+	AuditActionRequestPriority = "request.priority"
+	// AuditActionPromptUnverify is logged when a moderator unverifies a prompt or an edit to its content does
+	AuditActionPromptUnverify = "prompt.unverify"
+)
+
+// MaxAuditReasonLength caps AuditEntry.Reason
+const MaxAuditReasonLength = 500
+
+// UnverifyAudit records a prompt losing its verification
+func UnverifyAudit(promptID, actorID uint, reason string) AuditEntry {
+	return AuditEntry{
+		ActorID:  actorID,
+		Action:   AuditActionPromptUnverify,
+		TargetID: promptID,
+		OldValue: "verified",
+		NewValue: "unverified",
+		Reason:   reason,
+	}
+}
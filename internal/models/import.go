@@ -0,0 +1,9 @@
+package models
+
+// GitHubImportRequest points at a directory (or a single file) of Markdown prompts in a GitHub repository
+// This is for POST /api/v1/prompts/import/github
+type GitHubImportRequest struct {
+	RepoURL string `json:"repo_url"`      // https://github.com/owner/repo or owner/repo
+	Path    string `json:"path"`          // Directory inside the repo, "" for the root
+	Ref     string `json:"ref,omitempty"` // Branch, tag or commit, default branch when empty
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Model replaces gorm.Model as the embedded base for every table
+// Same columns, but with snake_case json tags and DeletedAt never serialized,
+// so a model returned directly (e.g. in an export) can't leak soft-delete state
+type Model struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestModelJSONHidesDeletedAt(t *testing.T) {
+	deleted := Model{
+		ID:        7,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+		DeletedAt: gorm.DeletedAt{Time: time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "prompt", value: Prompt{Model: deleted, Title: "Two Sum"}},
+		{name: "user", value: User{Model: deleted, Username: "ann"}},
+		{name: "request", value: PromptRequest{Model: deleted, RequestedTitle: "Two Sum"}},
+		{name: "translation", value: PromptTranslation{Model: deleted, Locale: "fr"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal(encoded, &fields); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			for _, key := range []string{"deleted_at", "DeletedAt", "ID", "CreatedAt", "UpdatedAt"} {
+				if _, ok := fields[key]; ok {
+					t.Errorf("%s serialized: %s", key, encoded)
+				}
+			}
+			if fields["id"] != float64(7) || fields["created_at"] != "2024-05-01T12:00:00Z" || fields["updated_at"] != "2024-05-02T12:00:00Z" {
+				t.Errorf("id/created_at/updated_at missing or wrong: %s", encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+// Pagination defaults shared by every list endpoint
+const (
+	DefaultPage  = 1
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// PaginationParams holds the page/limit pair list endpoints take
+// Similar to req.query.page / req.query.limit in Express.js
+type PaginationParams struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+// Normalize falls back to the defaults for out-of-range values
+func (p *PaginationParams) Normalize() {
+	if p.Page < 1 {
+		p.Page = DefaultPage
+	}
+	if p.Limit < 1 || p.Limit > MaxLimit {
+		p.Limit = DefaultLimit
+	}
+}
+
+// Offset is the number of rows to skip for the current page
+func (p PaginationParams) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// TotalPages is how many pages total rows span at the current limit
+func (p PaginationParams) TotalPages(total int64) int {
+	return int((total + int64(p.Limit) - 1) / int64(p.Limit))
+}
@@ -0,0 +1,40 @@
+package models
+
+import "testing"
+
+func TestPaginationNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   PaginationParams
+		want PaginationParams
+	}{
+		{name: "in range", in: PaginationParams{Page: 3, Limit: 25}, want: PaginationParams{Page: 3, Limit: 25}},
+		{name: "zero values", in: PaginationParams{}, want: PaginationParams{Page: DefaultPage, Limit: DefaultLimit}},
+		{name: "negative", in: PaginationParams{Page: -1, Limit: -5}, want: PaginationParams{Page: DefaultPage, Limit: DefaultLimit}},
+		{name: "limit at max", in: PaginationParams{Page: 1, Limit: MaxLimit}, want: PaginationParams{Page: 1, Limit: MaxLimit}},
+		{name: "limit past max", in: PaginationParams{Page: 1, Limit: MaxLimit + 1}, want: PaginationParams{Page: 1, Limit: DefaultLimit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			got.Normalize()
+			if got != tt.want {
+				t.Errorf("Normalize(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationOffsetAndTotalPages(t *testing.T) {
+	p := PaginationParams{Page: 3, Limit: 10}
+	if got := p.Offset(); got != 20 {
+		t.Errorf("Offset = %d, want 20", got)
+	}
+
+	for total, want := range map[int64]int{0: 0, 1: 1, 10: 1, 11: 2, 95: 10} {
+		if got := p.TotalPages(total); got != want {
+			t.Errorf("TotalPages(%d) = %d, want %d", total, got, want)
+		}
+	}
+}
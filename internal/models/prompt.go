@@ -1,12 +1,14 @@
 package models
 
 import (
+	"fmt"
 	"gorm.io/gorm"
+	"slices"
 	"time"
 )
 
 type Prompt struct {
-	gorm.Model
+	Model
 
 	Title       string `gorm:"not null;size:200" json:"title"`
 	Description string `gorm:"type:text;not null" json:"description"`
@@ -21,6 +23,9 @@ type Prompt struct {
 
 	// Prompt content and examples
 	ProblemStatement string `gorm:"type:text;not null" json:"problem_statement"`
+	Examples         string `gorm:"type:text;not null;default:''" json:"examples"` // Defaults backfill rows created before these columns
+	Hints            string `gorm:"type:text;not null;default:''" json:"hints"`
+	EstimatedTime    int    `gorm:"not null;default:0" json:"estimated_time"` // Minutes, 0 when unknown
 
 	// Quality control
 	IsVerified bool       `gorm:"default:false;index" json:"is_verified"`
@@ -28,8 +33,9 @@ type Prompt struct {
 	VerifiedAt *time.Time `json:"verified_at,omitempty"`
 
 	// Engagement metrics
-	ViewCount      int `gorm:"default:0" json:"view_count"`
-	LikeCount      int `gorm:"default:0" json:"like_count"`
+	// View/like counts live in prompt_counts and are joined in on read (read-only here)
+	ViewCount      int `gorm:"->;-:migration" json:"view_count"`
+	LikeCount      int `gorm:"->;-:migration" json:"like_count"`
 	DifficultyVote int `gorm:"default:0" json:"difficulty_vote"` // Average difficulty rating
 
 	Tags string `gorm:"type:text" json:"tags"` // JSON array of tags
@@ -38,6 +44,9 @@ type Prompt struct {
 	AuthorID    *uint  `gorm:"index" json:"author_id,omitempty"`
 	AuthorName  string `gorm:"size:100" json:"author_name,omitempty"`
 	AuthorEmail string `gorm:"size:100" json:"author_email,omitempty"`
+
+	// Id assigned by an external system that syncs prompts in, NULL for native prompts
+	ExternalID *string `gorm:"size:100;uniqueIndex" json:"external_id,omitempty"`
 }
 
 type DifficultyLevel string
@@ -49,19 +58,23 @@ const (
 	DifficultyExpert       DifficultyLevel = "expert"
 )
 
+// DifficultyLevels lists every difficulty, easiest first
+var DifficultyLevels = []DifficultyLevel{DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced, DifficultyExpert}
+
 // Valid checks if the difficulty level is valid
 func (d DifficultyLevel) Valid() bool {
-	switch d {
-	case DifficultyBeginner, DifficultyIntermediate, DifficultyAdvanced, DifficultyExpert:
-		return true
-	}
-	return false
+	return slices.Contains(DifficultyLevels, d)
 }
 
 func (Prompt) TableName() string {
 	return "prompts"
 }
 
+// ETag identifies this version of the prompt, it changes whenever the prompt is saved
+func (p *Prompt) ETag() string {
+	return fmt.Sprintf(`"%d-%x"`, p.ID, p.UpdatedAt.UnixNano())
+}
+
 func (p *Prompt) BeforeCreate(tx *gorm.DB) error {
 	// Validate difficulty level
 	if !p.Difficulty.Valid() {
@@ -77,13 +90,48 @@ type PromptFilter struct {
 	Difficulty DifficultyLevel `json:"difficulty,omitempty"`
 	Category   string          `json:"category,omitempty"`
 	IsVerified *bool           `json:"is_verified,omitempty"`
+	AuthorID   *uint           `json:"author_id,omitempty"`
 	Search     string          `json:"search,omitempty"` // Search in title/description
-	Page       int             `json:"page"`
-	Limit      int             `json:"limit"`
+	Sort       PromptSort      `json:"sort,omitempty"`
+	IDs        []uint          `json:"ids,omitempty"`     // Restrict to these prompt ids
+	Ordered    bool            `json:"ordered,omitempty"` // Return IDs in the given order instead of Sort
+	Seed       string          `json:"seed,omitempty"`    // Stable pseudo-random order per seed, instead of Sort
+
+	// Set by the server for callers below MIN_ROLE_TO_VIEW_UNVERIFIED, never from client input
+	VerifiedOnly bool `json:"-"`
+}
+
+// MaxSeedLength caps the ?seed= value used for seeded ordering
+const MaxSeedLength = 100
+
+// PromptSort represents the available orderings for prompt listings
+type PromptSort string
+
+const (
+	SortNewest        PromptSort = "newest"
+	SortOldest        PromptSort = "oldest"
+	SortPopular       PromptSort = "popular"        // Most viewed first
+	SortVerifiedFirst PromptSort = "verified_first" // Verified prompts first, then newest
+	SortTitle         PromptSort = "title"          // Alphabetical, case-insensitive
+	SortQuality       PromptSort = "quality"        // Highest quality_score first
+)
+
+// Valid checks if the sort option is valid
+func (s PromptSort) Valid() bool {
+	switch s {
+	case SortNewest, SortOldest, SortPopular, SortVerifiedFirst, SortTitle, SortQuality:
+		return true
+	}
+	return false
 }
 
 // PromptCreateRequest represents the request to create a new prompt
 // Similar to DTO (Data Transfer Object) in Java
+//
+// Server-controlled fields are deliberately absent so they can't be mass-assigned
+// from a request body: ID, timestamps, IsVerified, VerifiedBy, VerifiedAt,
+// ViewCount, LikeCount, DifficultyVote and AuthorID. Unknown keys like
+// "is_verified" are dropped on decode and never reach the model
 type PromptCreateRequest struct {
 	Title            string          `json:"title" validate:"required,max=200"`
 	Description      string          `json:"description" validate:"required"`
@@ -100,6 +148,7 @@ type PromptCreateRequest struct {
 }
 
 // ToPrompt converts PromptCreateRequest to Prompt model
+// Only client-writable fields are copied, server-controlled ones keep their zero/default values
 func (req *PromptCreateRequest) ToPrompt() *Prompt {
 	return &Prompt{
 		Title:            req.Title,
@@ -108,9 +157,122 @@ func (req *PromptCreateRequest) ToPrompt() *Prompt {
 		Difficulty:       req.Difficulty,
 		Category:         req.Category,
 		ProblemStatement: req.ProblemStatement,
+		Examples:         req.Examples,
+		Hints:            req.Hints,
+		EstimatedTime:    req.EstimatedTime,
 
 		Tags:        req.Tags,
 		AuthorName:  req.AuthorName,
 		AuthorEmail: req.AuthorEmail,
 	}
 }
+
+// ApplyTo copies the client-writable fields onto an existing prompt
+// Used by upserts, server-controlled fields and counters are left untouched
+func (req *PromptCreateRequest) ApplyTo(prompt *Prompt) {
+	prompt.Title = req.Title
+	prompt.Description = req.Description
+	prompt.Language = req.Language
+	prompt.Difficulty = req.Difficulty
+	prompt.Category = req.Category
+	prompt.ProblemStatement = req.ProblemStatement
+	prompt.Examples = req.Examples
+	prompt.Hints = req.Hints
+	prompt.EstimatedTime = req.EstimatedTime
+
+	prompt.Tags = req.Tags
+	prompt.AuthorName = req.AuthorName
+	prompt.AuthorEmail = req.AuthorEmail
+}
+
+// PromptUnverifyRequest is the body of POST /prompts/:id/unverify
+// The reason is required and kept in the audit log
+type PromptUnverifyRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// PromptUpdateRequest represents a partial edit of a prompt (PUT /prompts/:id)
+// Only non-nil fields are applied, server-controlled fields are absent like on create
+type PromptUpdateRequest struct {
+	Title            *string          `json:"title,omitempty"`
+	Description      *string          `json:"description,omitempty"`
+	Language         *string          `json:"language,omitempty"`
+	Difficulty       *DifficultyLevel `json:"difficulty,omitempty"`
+	Category         *string          `json:"category,omitempty"`
+	ProblemStatement *string          `json:"problem_statement,omitempty"`
+	Examples         *string          `json:"examples,omitempty"`
+	Hints            *string          `json:"hints,omitempty"`
+	EstimatedTime    *int             `json:"estimated_time,omitempty"`
+	Tags             *string          `json:"tags,omitempty"`
+	AuthorName       *string          `json:"author_name,omitempty"`
+	AuthorEmail      *string          `json:"author_email,omitempty"`
+}
+
+// ApplyTo copies the fields that were sent onto an existing prompt
+func (req *PromptUpdateRequest) ApplyTo(prompt *Prompt) {
+	if req.Title != nil {
+		prompt.Title = *req.Title
+	}
+	if req.Description != nil {
+		prompt.Description = *req.Description
+	}
+	if req.Language != nil {
+		prompt.Language = *req.Language
+	}
+	if req.Difficulty != nil {
+		prompt.Difficulty = *req.Difficulty
+	}
+	if req.Category != nil {
+		prompt.Category = *req.Category
+	}
+	if req.ProblemStatement != nil {
+		prompt.ProblemStatement = *req.ProblemStatement
+	}
+	if req.Examples != nil {
+		prompt.Examples = *req.Examples
+	}
+	if req.Hints != nil {
+		prompt.Hints = *req.Hints
+	}
+	if req.EstimatedTime != nil {
+		prompt.EstimatedTime = *req.EstimatedTime
+	}
+	if req.Tags != nil {
+		prompt.Tags = *req.Tags
+	}
+	if req.AuthorName != nil {
+		prompt.AuthorName = *req.AuthorName
+	}
+	if req.AuthorEmail != nil {
+		prompt.AuthorEmail = *req.AuthorEmail
+	}
+}
+
+// ChangesContent reports whether applying req would change what a moderator verified:
+// the title, description, language, difficulty, category, problem statement, examples or hints
+func (req *PromptUpdateRequest) ChangesContent(prompt *Prompt) bool {
+	changes := func(sent *string, current string) bool { return sent != nil && *sent != current }
+
+	return changes(req.Title, prompt.Title) ||
+		changes(req.Description, prompt.Description) ||
+		changes(req.Language, prompt.Language) ||
+		(req.Difficulty != nil && *req.Difficulty != prompt.Difficulty) ||
+		changes(req.Category, prompt.Category) ||
+		changes(req.ProblemStatement, prompt.ProblemStatement) ||
+		changes(req.Examples, prompt.Examples) ||
+		changes(req.Hints, prompt.Hints)
+}
+
+// PromptMergeRequest asks to fold duplicate prompts into a single kept prompt
+type PromptMergeRequest struct {
+	Keep  uint   `json:"keep" validate:"required"`
+	Merge []uint `json:"merge" validate:"required,min=1"`
+}
+
+// BulkTagRequest adds and/or removes tags on many prompts at once
+// Removal is case-insensitive and happens before the additions
+type BulkTagRequest struct {
+	IDs    []uint   `json:"ids" validate:"required,min=1,max=100"`
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// PromptCount holds the engagement counters for a prompt
+// Kept out of the prompts table so hot increments don't lock the row prompt edits need
+type PromptCount struct {
+	PromptID  uint `gorm:"primaryKey;autoIncrement:false" json:"prompt_id"`
+	ViewCount int  `gorm:"not null;default:0" json:"view_count"`
+	LikeCount int  `gorm:"not null;default:0" json:"like_count"`
+}
+
+// TableName specifies the table name for GORM
+func (PromptCount) TableName() string {
+	return "prompt_counts"
+}
+
+// PromptCountSnapshot is a periodic copy of a prompt's counters, the baseline for count deltas
+type PromptCountSnapshot struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PromptID  uint      `gorm:"not null;index:idx_count_snapshots_prompt_taken,priority:1" json:"prompt_id"`
+	ViewCount int       `gorm:"not null;default:0" json:"view_count"`
+	LikeCount int       `gorm:"not null;default:0" json:"like_count"`
+	TakenAt   time.Time `gorm:"not null;index;index:idx_count_snapshots_prompt_taken,priority:2" json:"taken_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PromptCountSnapshot) TableName() string {
+	return "prompt_count_snapshots"
+}
+
+// PromptCountDelta is how much a prompt's counters moved since the snapshot at BaselineAt
+// Without a snapshot old enough BaselineAt is nil and the deltas are the full counts
+type PromptCountDelta struct {
+	PromptID   uint       `json:"prompt_id"`
+	ViewCount  int        `json:"view_count"`
+	LikeCount  int        `json:"like_count"`
+	ViewDelta  int        `json:"view_delta"`
+	LikeDelta  int        `json:"like_delta"`
+	BaselineAt *time.Time `json:"baseline_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PromptLike records that a user liked a prompt, one row per (prompt, user)
+// The composite primary key is what makes liking idempotent
+type PromptLike struct {
+	PromptID  uint      `gorm:"primaryKey;autoIncrement:false" json:"prompt_id"`
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PromptLike) TableName() string {
+	return "prompt_likes"
+}
@@ -0,0 +1,65 @@
+package models
+
+import "testing"
+
+func TestPromptUpdateRequestApplyTo(t *testing.T) {
+	prompt := Prompt{
+		Title:       "Two Sum",
+		Description: "Find a pair",
+		Language:    "go",
+		Difficulty:  DifficultyBeginner,
+		Tags:        `["arrays"]`,
+		AuthorName:  "Ann",
+	}
+
+	title := "Three Sum"
+	difficulty := DifficultyIntermediate
+	req := PromptUpdateRequest{Title: &title, Difficulty: &difficulty}
+	req.ApplyTo(&prompt)
+
+	want := Prompt{
+		Title:       "Three Sum",
+		Description: "Find a pair",
+		Language:    "go",
+		Difficulty:  DifficultyIntermediate,
+		Tags:        `["arrays"]`,
+		AuthorName:  "Ann",
+	}
+	if prompt != want {
+		t.Errorf("ApplyTo = %+v, want %+v", prompt, want)
+	}
+}
+
+func TestPromptUpdateRequestChangesContent(t *testing.T) {
+	prompt := &Prompt{Title: "Two Sum", Difficulty: DifficultyBeginner, Hints: "Use a map", Tags: `["arrays"]`}
+
+	same := "Two Sum"
+	other := "Three Sum"
+	beginner := DifficultyBeginner
+	expert := DifficultyExpert
+	tags := `["maps"]`
+	minutes := 20
+
+	tests := []struct {
+		name string
+		req  PromptUpdateRequest
+		want bool
+	}{
+		{name: "empty", req: PromptUpdateRequest{}},
+		{name: "same title", req: PromptUpdateRequest{Title: &same}},
+		{name: "new title", req: PromptUpdateRequest{Title: &other}, want: true},
+		{name: "same difficulty", req: PromptUpdateRequest{Difficulty: &beginner}},
+		{name: "new difficulty", req: PromptUpdateRequest{Difficulty: &expert}, want: true},
+		{name: "new hints", req: PromptUpdateRequest{Hints: &other}, want: true},
+		{name: "tags only", req: PromptUpdateRequest{Tags: &tags}},
+		{name: "estimated time only", req: PromptUpdateRequest{EstimatedTime: &minutes}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.ChangesContent(prompt); got != tt.want {
+				t.Errorf("ChangesContent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+// PromptTranslation holds localized content for a prompt
+// The prompt row itself carries the default-language content
+type PromptTranslation struct {
+	Model
+
+	PromptID uint   `gorm:"not null;uniqueIndex:idx_prompt_translation_locale" json:"prompt_id"`
+	Locale   string `gorm:"not null;size:20;uniqueIndex:idx_prompt_translation_locale" json:"locale"` // e.g. "fr", "pt-br"
+
+	Title            string `gorm:"not null;size:200" json:"title"`
+	Description      string `gorm:"type:text;not null" json:"description"`
+	ProblemStatement string `gorm:"type:text;not null" json:"problem_statement"`
+}
+
+// TableName specifies the table name for GORM
+func (PromptTranslation) TableName() string {
+	return "prompt_translations"
+}
+
+// PromptTranslationRequest is the body for adding or replacing a translation
+// The locale comes from the URL
+type PromptTranslationRequest struct {
+	Title            string `json:"title" validate:"required,max=200"`
+	Description      string `json:"description" validate:"required"`
+	ProblemStatement string `json:"problem_statement" validate:"required"`
+}
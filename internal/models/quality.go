@@ -0,0 +1,14 @@
+package models
+
+// QualityWeights tunes how much each signal contributes to a prompt's quality score
+// Each signal is normalized to 0..1, so the maximum score is the sum of the weights
+type QualityWeights struct {
+	Verified     float64 // is_verified
+	Engagement   float64 // likes per view, capped at 1
+	Completeness float64 // share of learning aids present (examples, hints)
+	Freshness    float64 // decays with age: 1 / (1 + age/QualityFreshnessDays)
+}
+
+// QualityFreshnessDays is the age at which the freshness signal has halved
+// Shared by the Go score and the SQL ordering so both rank prompts the same way
+const QualityFreshnessDays = 30
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+
 	"gorm.io/gorm"
 )
 
@@ -8,7 +11,7 @@ import (
 // This is when users want to request specific prompts to be created by authorized users
 // Similar to a ContactForm or RequestForm model in Express.js
 type PromptRequest struct {
-	gorm.Model
+	Model
 
 	// Requester information (anyone can submit requests)
 	RequesterName  string `gorm:"not null;size:100" json:"requester_name"`
@@ -41,6 +44,9 @@ type PromptRequest struct {
 	CompletedPromptID *uint  `gorm:"index" json:"completed_prompt_id,omitempty"` // Link to created prompt
 	CompletedAt       *int64 `json:"completed_at,omitempty"`
 
+	// Set when triage closes this request in favour of an earlier one
+	DuplicateOfID *uint `gorm:"index" json:"duplicate_of_id,omitempty"`
+
 	// Communication
 	AdminNotes      string `gorm:"type:text" json:"admin_notes,omitempty"`      // Internal notes
 	ResponseMessage string `gorm:"type:text" json:"response_message,omitempty"` // Response to requester
@@ -68,14 +74,33 @@ const (
 	StatusOnHold     RequestStatus = "on_hold"     // Temporarily paused
 )
 
+// RequestStatuses lists every request status in workflow order
+var RequestStatuses = []RequestStatus{
+	StatusPending, StatusInReview, StatusApproved, StatusAssigned,
+	StatusInProgress, StatusCompleted, StatusRejected, StatusOnHold,
+}
+
 // Valid checks if the request status is valid
 func (s RequestStatus) Valid() bool {
-	switch s {
-	case StatusPending, StatusInReview, StatusApproved, StatusAssigned,
-		StatusInProgress, StatusCompleted, StatusRejected, StatusOnHold:
-		return true
-	}
-	return false
+	return slices.Contains(RequestStatuses, s)
+}
+
+// requestTransitions lists where each status may move next
+// Completed is final, a rejected request can only be reopened as pending
+var requestTransitions = map[RequestStatus][]RequestStatus{
+	StatusPending:    {StatusInReview, StatusApproved, StatusRejected, StatusOnHold},
+	StatusInReview:   {StatusPending, StatusApproved, StatusRejected, StatusOnHold},
+	StatusApproved:   {StatusAssigned, StatusInProgress, StatusRejected, StatusOnHold},
+	StatusAssigned:   {StatusApproved, StatusInProgress, StatusCompleted, StatusRejected, StatusOnHold},
+	StatusInProgress: {StatusAssigned, StatusCompleted, StatusRejected, StatusOnHold},
+	StatusOnHold:     {StatusPending, StatusInReview, StatusApproved, StatusAssigned, StatusInProgress, StatusRejected},
+	StatusRejected:   {StatusPending},
+	StatusCompleted:  {},
+}
+
+// CanTransitionTo reports whether a request may move from s to next, staying put is always allowed
+func (s RequestStatus) CanTransitionTo(next RequestStatus) bool {
+	return s == next || slices.Contains(requestTransitions[s], next)
 }
 
 // Priority represents the priority level of a request
@@ -88,13 +113,12 @@ const (
 	PriorityUrgent Priority = "urgent"
 )
 
+// Priorities lists every priority, lowest first
+var Priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
 // Valid checks if the priority is valid
 func (p Priority) Valid() bool {
-	switch p {
-	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
-		return true
-	}
-	return false
+	return slices.Contains(Priorities, p)
 }
 
 // TableName specifies the table name for GORM
@@ -123,9 +147,25 @@ func (pr *PromptRequest) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// MarkDuplicateOf closes the request as a duplicate of canonical
+// It is rejected with a response message pointing the requester at the canonical request
+// Fails, leaving pr untouched, when pr's status can't move to rejected (e.g. completed)
+func (pr *PromptRequest) MarkDuplicateOf(canonical *PromptRequest) error {
+	if !pr.Status.CanTransitionTo(StatusRejected) {
+		return fmt.Errorf("status transition not allowed: %s -> %s", pr.Status, StatusRejected)
+	}
+
+	pr.DuplicateOfID = &canonical.ID
+	pr.Status = StatusRejected
+	pr.IsRejected = true
+	pr.ResponseMessage = fmt.Sprintf("This request duplicates request #%d (%q), please follow that one for updates.", canonical.ID, canonical.RequestedTitle)
+	return nil
+}
+
 // PromptRequestCreateRequest represents the public form submission
 // This is what comes from the frontend form - POST /api/requests
 // Similar to req.body in Express.js contact forms
+// Status, assignment, completion and admin notes are server-controlled and not accepted here
 type PromptRequestCreateRequest struct {
 	RequesterName        string          `json:"requester_name" validate:"required,max=100"`
 	RequesterEmail       string          `json:"requester_email" validate:"required,email,max=100"`
@@ -152,6 +192,17 @@ type PromptRequestUpdateRequest struct {
 	CompletedPromptID *uint          `json:"completed_prompt_id,omitempty"`
 }
 
+// DuplicateOfRequest names the canonical request a duplicate is closed in favour of
+type DuplicateOfRequest struct {
+	DuplicateOfID uint `json:"duplicate_of_id" validate:"required"`
+}
+
+// BulkPriorityRequest moves many requests to the same priority at once
+type BulkPriorityRequest struct {
+	IDs      []uint   `json:"ids" validate:"required,min=1,max=100"`
+	Priority Priority `json:"priority" validate:"required"`
+}
+
 // ToPromptRequest converts PromptRequestCreateRequest to PromptRequest model
 // Similar to creating a new model instance from request body in Express.js
 func (req *PromptRequestCreateRequest) ToPromptRequest() *PromptRequest {
@@ -206,6 +257,7 @@ type PromptRequestResponse struct {
 	Status              RequestStatus   `json:"status"`
 	Priority            Priority        `json:"priority"`
 	CompletedPromptID   *uint           `json:"completed_prompt_id,omitempty"`
+	DuplicateOfID       *uint           `json:"duplicate_of_id,omitempty"`
 	ResponseMessage     string          `json:"response_message,omitempty"`
 	CreatedAt           int64           `json:"created_at"`
 	UpdatedAt           int64           `json:"updated_at"`
@@ -226,6 +278,7 @@ func (pr *PromptRequest) ToResponse() *PromptRequestResponse {
 		Status:              pr.Status,
 		Priority:            pr.Priority,
 		CompletedPromptID:   pr.CompletedPromptID,
+		DuplicateOfID:       pr.DuplicateOfID,
 		ResponseMessage:     pr.ResponseMessage,
 		CreatedAt:           pr.CreatedAt.Unix(),
 		UpdatedAt:           pr.UpdatedAt.Unix(),
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkDuplicateOf(t *testing.T) {
+	canonical := &PromptRequest{Model: Model{ID: 12}, RequestedTitle: "Binary search drills"}
+	request := &PromptRequest{Model: Model{ID: 40}, Status: StatusInReview}
+
+	if err := request.MarkDuplicateOf(canonical); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if request.DuplicateOfID == nil || *request.DuplicateOfID != 12 {
+		t.Errorf("DuplicateOfID = %v, want 12", request.DuplicateOfID)
+	}
+	if request.Status != StatusRejected || !request.IsRejected {
+		t.Errorf("status = %s rejected = %v, want rejected", request.Status, request.IsRejected)
+	}
+	if !strings.Contains(request.ResponseMessage, "#12") || !strings.Contains(request.ResponseMessage, `"Binary search drills"`) {
+		t.Errorf("response message %q doesn't reference the canonical request", request.ResponseMessage)
+	}
+}
+
+func TestMarkDuplicateOfRespectsTransitions(t *testing.T) {
+	canonical := &PromptRequest{Model: Model{ID: 12}}
+	request := &PromptRequest{Model: Model{ID: 40}, Status: StatusCompleted, ResponseMessage: "Done, see prompt #7"}
+
+	err := request.MarkDuplicateOf(canonical)
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("error = %v, want a refused transition", err)
+	}
+	if request.Status != StatusCompleted || request.DuplicateOfID != nil || request.ResponseMessage != "Done, see prompt #7" {
+		t.Errorf("refused request was modified: %+v", request)
+	}
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to RequestStatus
+		want     bool
+	}{
+		{StatusPending, StatusInReview, true},
+		{StatusPending, StatusRejected, true},
+		{StatusPending, StatusCompleted, false},
+		{StatusInReview, StatusApproved, true},
+		{StatusApproved, StatusAssigned, true},
+		{StatusApproved, StatusPending, false},
+		{StatusAssigned, StatusCompleted, true},
+		{StatusInProgress, StatusCompleted, true},
+		{StatusOnHold, StatusInProgress, true},
+		{StatusOnHold, StatusCompleted, false},
+		{StatusRejected, StatusPending, true},
+		{StatusRejected, StatusApproved, false},
+		{StatusCompleted, StatusPending, false},
+		{StatusCompleted, StatusRejected, false},
+		{StatusCompleted, StatusCompleted, true},
+		{StatusPending, RequestStatus("bogus"), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// Every status needs an entry, or it would silently become a dead end
+func TestEveryStatusHasTransitions(t *testing.T) {
+	for _, status := range RequestStatuses {
+		if _, ok := requestTransitions[status]; !ok {
+			t.Errorf("no transitions listed for %s", status)
+		}
+		if !status.CanTransitionTo(status) {
+			t.Errorf("%s can't stay put", status)
+		}
+	}
+}
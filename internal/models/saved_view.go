@@ -0,0 +1,43 @@
+package models
+
+import "encoding/json"
+
+// SavedView is a user's named PromptFilter, e.g. "unverified Go intermediate"
+// The filter is stored as JSON so new filter fields don't need a migration
+type SavedView struct {
+	Model
+
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	Name   string `gorm:"not null;size:100" json:"name"`
+	Filter string `gorm:"type:text;not null" json:"-"` // JSON-encoded PromptFilter
+}
+
+// TableName specifies the table name for GORM
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// SetFilter serializes filter into the stored JSON
+func (v *SavedView) SetFilter(filter PromptFilter) error {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	v.Filter = string(data)
+	return nil
+}
+
+// GetFilter decodes the stored filter, an empty filter if it can't be read
+func (v *SavedView) GetFilter() PromptFilter {
+	var filter PromptFilter
+	if v.Filter != "" {
+		json.Unmarshal([]byte(v.Filter), &filter)
+	}
+	return filter
+}
+
+// SavedViewRequest is the body for creating or replacing a saved view
+type SavedViewRequest struct {
+	Name   string       `json:"name" validate:"required,max=100"`
+	Filter PromptFilter `json:"filter"`
+}
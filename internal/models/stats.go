@@ -0,0 +1,47 @@
+package models
+
+// LanguageEngagement aggregates prompts and their engagement for one language
+type LanguageEngagement struct {
+	Language      string  `json:"language"`
+	PromptCount   int64   `json:"prompt_count"`
+	TotalViews    int64   `json:"total_views"`
+	TotalLikes    int64   `json:"total_likes"`
+	AvgDifficulty float64 `json:"avg_difficulty"` // beginner=1 ... expert=4
+}
+
+// LanguageEngagementSort represents the available orderings for the language leaderboard
+type LanguageEngagementSort string
+
+const (
+	EngagementSortViews   LanguageEngagementSort = "views"
+	EngagementSortLikes   LanguageEngagementSort = "likes"
+	EngagementSortPrompts LanguageEngagementSort = "prompts"
+)
+
+// Valid checks if the sort option is valid
+func (s LanguageEngagementSort) Valid() bool {
+	switch s {
+	case EngagementSortViews, EngagementSortLikes, EngagementSortPrompts:
+		return true
+	}
+	return false
+}
+
+// TagUsage counts the live prompts carrying a tag
+type TagUsage struct {
+	Tag         string `json:"tag"`
+	PromptCount int64  `json:"prompt_count"`
+}
+
+const (
+	DefaultTrendingTagsLimit = 10
+	MaxTrendingTagsLimit     = 50
+)
+
+// AuthorSummary aggregates one author's live prompts and their engagement
+type AuthorSummary struct {
+	PromptCount   int64 `json:"prompt_count"`
+	VerifiedCount int64 `json:"verified_count"`
+	TotalViews    int64 `json:"total_views"`
+	TotalLikes    int64 `json:"total_likes"`
+}
@@ -2,13 +2,16 @@ package models
 
 import (
 	"encoding/json"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"slices"
+	"strings"
 )
 
 // User represents authorized users who can create/edit/delete prompts
 // Similar to User model in Express.js apps with roles
 type User struct {
-	gorm.Model
+	Model
 
 	// Basic information
 	Name     string `gorm:"not null;size:100" json:"name"`
@@ -48,23 +51,36 @@ type UserRole string
 
 const (
 	RoleContributor UserRole = "contributor" // Can create and edit their own prompts
+	RoleIntegrator  UserRole = "integrator"  // Service account that syncs prompts in by external id
 	RoleModerator   UserRole = "moderator"   // Can verify prompts, manage requests
 	RoleAdmin       UserRole = "admin"       // Full access to everything
 	RoleSuperAdmin  UserRole = "super_admin" // System administration
 )
 
+// UserRoles lists every role, least privileged first
+var UserRoles = []UserRole{RoleContributor, RoleIntegrator, RoleModerator, RoleAdmin, RoleSuperAdmin}
+
+// RoleAnonymous names callers without an account in role thresholds, it is never a stored role
+const RoleAnonymous = "anonymous"
+
 // Valid checks if the user role is valid
 func (r UserRole) Valid() bool {
-	switch r {
-	case RoleContributor, RoleModerator, RoleAdmin, RoleSuperAdmin:
-		return true
-	}
-	return false
+	return slices.Contains(UserRoles, r)
+}
+
+// AtLeast reports whether r ranks at or above min, false when min isn't a valid role
+func (r UserRole) AtLeast(min UserRole) bool {
+	return min.Valid() && slices.Index(UserRoles, r) >= slices.Index(UserRoles, min)
 }
 
 // CanCreatePrompts checks if user can create prompts
 func (r UserRole) CanCreatePrompts() bool {
-	return r == RoleContributor || r == RoleModerator || r == RoleAdmin || r == RoleSuperAdmin
+	return r == RoleContributor || r == RoleIntegrator || r == RoleModerator || r == RoleAdmin || r == RoleSuperAdmin
+}
+
+// CanSyncPrompts checks if user can create or overwrite prompts by external id
+func (r UserRole) CanSyncPrompts() bool {
+	return r == RoleIntegrator || r == RoleModerator || r == RoleAdmin || r == RoleSuperAdmin
 }
 
 // CanVerifyPrompts checks if user can verify prompts
@@ -95,25 +111,38 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// UserCreateRequest represents request to create a new user (admin only)
-// Similar to user creation in Express.js admin panels
+// BeforeSave hook - runs on create and update
+// Emails are stored lowercased so the unique index treats Foo@bar.com and foo@bar.com as the same
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.Email = NormalizeEmail(u.Email)
+	return nil
+}
+
+// NormalizeEmail trims and lowercases an email address for storage and lookups
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// UserCreateRequest represents request to create a new user
+// Anyone may register as a contributor, only admins can pick another role
 type UserCreateRequest struct {
 	Name     string   `json:"name" validate:"required,max=100"`
 	Email    string   `json:"email" validate:"required,email,max=100"`
 	Username string   `json:"username" validate:"required,min=3,max=50"`
 	Password string   `json:"password" validate:"required,min=8"`
 	Role     UserRole `json:"role,omitempty"`
-	Bio      string   `json:"bio,omitempty"`
+	Bio      string   `json:"bio,omitempty" validate:"max=1000"`
 	Website  string   `json:"website,omitempty" validate:"omitempty,url"`
 }
 
 // UserUpdateRequest represents updates to user profile
 // This is for PATCH /api/users/:id or /api/profile
+// Role, IsActive and the statistics counters are server-controlled, see UserAdminUpdateRequest
 type UserUpdateRequest struct {
 	Name            *string  `json:"name,omitempty" validate:"omitempty,max=100"`
 	Email           *string  `json:"email,omitempty" validate:"omitempty,email,max=100"`
 	Username        *string  `json:"username,omitempty" validate:"omitempty,min=3,max=50"`
-	Bio             *string  `json:"bio,omitempty"`
+	Bio             *string  `json:"bio,omitempty" validate:"omitempty,max=1000"`
 	Website         *string  `json:"website,omitempty" validate:"omitempty,url"`
 	Avatar          *string  `json:"avatar,omitempty" validate:"omitempty,url"`
 	Location        *string  `json:"location,omitempty" validate:"omitempty,max=100"`
@@ -123,6 +152,55 @@ type UserUpdateRequest struct {
 	LinkedinProfile *string  `json:"linkedin_profile,omitempty" validate:"omitempty,url"`
 }
 
+// ApplyTo copies the fields that were sent onto an existing user
+// Specialties are serialized to the JSON column through SetSpecialties
+func (req *UserUpdateRequest) ApplyTo(user *User) error {
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.Bio != nil {
+		user.Bio = *req.Bio
+	}
+	if req.Website != nil {
+		user.Website = *req.Website
+	}
+	if req.Avatar != nil {
+		user.Avatar = *req.Avatar
+	}
+	if req.Location != nil {
+		user.Location = *req.Location
+	}
+	if req.Specialties != nil {
+		if err := user.SetSpecialties(req.Specialties); err != nil {
+			return err
+		}
+	}
+	if req.GithubUsername != nil {
+		user.GithubUsername = *req.GithubUsername
+	}
+	if req.TwitterUsername != nil {
+		user.TwitterUsername = *req.TwitterUsername
+	}
+	if req.LinkedinProfile != nil {
+		user.LinkedinProfile = *req.LinkedinProfile
+	}
+	return nil
+}
+
+// UserFilter represents filtering options for the user list
+// e.g. /api/v1/users?role=moderator&search=ann
+type UserFilter struct {
+	Role            UserRole `json:"role,omitempty"`
+	Search          string   `json:"search,omitempty"` // Search in name/username
+	IncludeInactive bool     `json:"include_inactive,omitempty"`
+}
+
 // UserAdminUpdateRequest for admin-only updates (role, status, etc.)
 type UserAdminUpdateRequest struct {
 	Role     *UserRole `json:"role,omitempty"`
@@ -151,6 +229,32 @@ type UserResponse struct {
 	LinkedinProfile string   `json:"linkedin_profile,omitempty"`
 	CreatedAt       int64    `json:"created_at"`
 	UpdatedAt       int64    `json:"updated_at"`
+
+	// Authored prompts per difficulty, only filled on profile lookups
+	DifficultyBreakdown map[DifficultyLevel]int64 `json:"difficulty_breakdown,omitempty"`
+}
+
+// PublicUserResponse is the profile shown to outside viewers
+// Same as UserResponse minus personal details (email, location)
+type PublicUserResponse struct {
+	ID              uint     `json:"id"`
+	Name            string   `json:"name"`
+	Username        string   `json:"username"`
+	Role            UserRole `json:"role"`
+	Bio             string   `json:"bio,omitempty"`
+	Website         string   `json:"website,omitempty"`
+	Avatar          string   `json:"avatar,omitempty"`
+	Specialties     []string `json:"specialties"`
+	PromptsCreated  int      `json:"prompts_created"`
+	PromptsVerified int      `json:"prompts_verified"`
+	RequestsHandled int      `json:"requests_handled"`
+	GithubUsername  string   `json:"github_username,omitempty"`
+	TwitterUsername string   `json:"twitter_username,omitempty"`
+	LinkedinProfile string   `json:"linkedin_profile,omitempty"`
+	CreatedAt       int64    `json:"created_at"`
+
+	// Authored prompts per difficulty, only filled on profile lookups
+	DifficultyBreakdown map[DifficultyLevel]int64 `json:"difficulty_breakdown,omitempty"`
 }
 
 // ToResponse converts User to UserResponse
@@ -189,6 +293,51 @@ func (u *User) ToResponse() *UserResponse {
 	}
 }
 
+// ToPublicResponse converts User to PublicUserResponse
+// Use this for anyone who isn't the user themselves or an admin
+func (u *User) ToPublicResponse() *PublicUserResponse {
+	specialties := u.GetSpecialties()
+	if specialties == nil {
+		specialties = []string{}
+	}
+
+	return &PublicUserResponse{
+		ID:              u.ID,
+		Name:            u.Name,
+		Username:        u.Username,
+		Role:            u.Role,
+		Bio:             u.Bio,
+		Website:         u.Website,
+		Avatar:          u.Avatar,
+		Specialties:     specialties,
+		PromptsCreated:  u.PromptsCreated,
+		PromptsVerified: u.PromptsVerified,
+		RequestsHandled: u.RequestsHandled,
+		GithubUsername:  u.GithubUsername,
+		TwitterUsername: u.TwitterUsername,
+		LinkedinProfile: u.LinkedinProfile,
+		CreatedAt:       u.CreatedAt.Unix(),
+	}
+}
+
+// CanViewPrivateDetails checks if viewer may see u's email and location
+// Only the user themselves and user managers can; nil viewer means anonymous
+func (u *User) CanViewPrivateDetails(viewer *User) bool {
+	if viewer == nil {
+		return false
+	}
+	return viewer.ID == u.ID || viewer.Role.CanManageUsers()
+}
+
+// ResponseFor serializes u for the given viewer
+// Returns *UserResponse for self/admins and *PublicUserResponse for everyone else
+func (u *User) ResponseFor(viewer *User) interface{} {
+	if u.CanViewPrivateDetails(viewer) {
+		return u.ToResponse()
+	}
+	return u.ToPublicResponse()
+}
+
 // SetSpecialties converts a slice of strings to JSON and sets it
 func (u *User) SetSpecialties(specialties []string) error {
 	data, err := json.Marshal(specialties)
@@ -199,6 +348,21 @@ func (u *User) SetSpecialties(specialties []string) error {
 	return nil
 }
 
+// SetPassword stores the bcrypt hash of password at the given cost (config BcryptCost)
+func (u *User) SetPassword(password string, cost int) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword reports whether password matches the stored hash
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
 // GetSpecialties returns specialties as a slice
 func (u *User) GetSpecialties() []string {
 	var specialties []string
@@ -217,6 +381,12 @@ func (req *UserCreateRequest) ToUser() *User {
 		Role:     req.Role,
 		Bio:      req.Bio,
 		Website:  req.Website,
-		// PasswordHash will be set separately after hashing
+		// PasswordHash will be set separately with SetPassword
 	}
 }
+
+// UserMergeRequest asks to fold a duplicate account into the kept one (admin only)
+type UserMergeRequest struct {
+	Keep  uint `json:"keep" validate:"required"`
+	Merge uint `json:"merge" validate:"required"`
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSetPasswordAndCheckPassword(t *testing.T) {
+	var user User
+	if err := user.SetPassword("correct horse", bcrypt.MinCost); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+
+	if user.PasswordHash == "" || strings.Contains(user.PasswordHash, "correct horse") {
+		t.Fatalf("hash %q looks wrong", user.PasswordHash)
+	}
+	if cost, err := bcrypt.Cost([]byte(user.PasswordHash)); err != nil || cost != bcrypt.MinCost {
+		t.Errorf("hash cost = %d, %v, want %d", cost, err, bcrypt.MinCost)
+	}
+
+	if !user.CheckPassword("correct horse") {
+		t.Error("right password rejected")
+	}
+	for _, wrong := range []string{"", "Correct horse", "correct horse "} {
+		if user.CheckPassword(wrong) {
+			t.Errorf("wrong password %q accepted", wrong)
+		}
+	}
+}
+
+func TestSetPasswordSaltsEachHash(t *testing.T) {
+	var a, b User
+	a.SetPassword("same password", bcrypt.MinCost)
+	b.SetPassword("same password", bcrypt.MinCost)
+
+	if a.PasswordHash == b.PasswordHash {
+		t.Error("two hashes of the same password are identical")
+	}
+}
+
+func TestCheckPasswordWithoutHash(t *testing.T) {
+	var user User
+	if user.CheckPassword("") {
+		t.Error("user without a hash accepted an empty password")
+	}
+}
+
+func TestSetPasswordRejectsInvalidCost(t *testing.T) {
+	var user User
+	if err := user.SetPassword("correct horse", bcrypt.MaxCost+1); err == nil {
+		t.Error("cost above bcrypt.MaxCost accepted")
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	tests := []struct {
+		role                        UserRole
+		create, sync, verify, users bool
+	}{
+		{role: RoleContributor, create: true},
+		{role: RoleIntegrator, create: true, sync: true},
+		{role: RoleModerator, create: true, sync: true, verify: true},
+		{role: RoleAdmin, create: true, sync: true, verify: true, users: true},
+		{role: RoleSuperAdmin, create: true, sync: true, verify: true, users: true},
+		{role: "owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if got := tt.role.CanCreatePrompts(); got != tt.create {
+				t.Errorf("CanCreatePrompts = %v, want %v", got, tt.create)
+			}
+			if got := tt.role.CanSyncPrompts(); got != tt.sync {
+				t.Errorf("CanSyncPrompts = %v, want %v", got, tt.sync)
+			}
+			if got := tt.role.CanVerifyPrompts(); got != tt.verify {
+				t.Errorf("CanVerifyPrompts = %v, want %v", got, tt.verify)
+			}
+			if got := tt.role.CanManageUsers(); got != tt.users {
+				t.Errorf("CanManageUsers = %v, want %v", got, tt.users)
+			}
+		})
+	}
+}
+
+func TestResponseFor(t *testing.T) {
+	user := &User{Model: Model{ID: 7}, Name: "Ann", Email: "ann@example.com", Username: "ann", Location: "Yangon"}
+
+	tests := []struct {
+		name        string
+		viewer      *User
+		wantPrivate bool
+	}{
+		{name: "anonymous"},
+		{name: "other contributor", viewer: &User{Model: Model{ID: 8}, Role: RoleContributor}},
+		{name: "moderator", viewer: &User{Model: Model{ID: 9}, Role: RoleModerator}},
+		{name: "self", viewer: &User{Model: Model{ID: 7}, Role: RoleContributor}, wantPrivate: true},
+		{name: "admin", viewer: &User{Model: Model{ID: 10}, Role: RoleAdmin}, wantPrivate: true},
+		{name: "super admin", viewer: &User{Model: Model{ID: 11}, Role: RoleSuperAdmin}, wantPrivate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := json.Marshal(user.ResponseFor(tt.viewer))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var fields map[string]interface{}
+			json.Unmarshal(encoded, &fields)
+
+			for _, private := range []string{"email", "location"} {
+				if _, ok := fields[private]; ok != tt.wantPrivate {
+					t.Errorf("%s present = %v, want %v (%s)", private, ok, tt.wantPrivate, encoded)
+				}
+			}
+			if fields["username"] != "ann" {
+				t.Errorf("username = %v, want ann", fields["username"])
+			}
+		})
+	}
+}
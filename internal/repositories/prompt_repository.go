@@ -3,21 +3,32 @@ package repositories
 import (
 	"PromptGallery/internal/models"
 	"errors"
+	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
+	"time"
 )
 
 type PromptRepository struct {
 	db *gorm.DB
+
+	// Postgres collation used for title ordering, empty falls back to LOWER(title)
+	titleCollation string
+
+	// Weights for ?sort=quality, must match the ones the service scores with
+	qualityWeights models.QualityWeights
 }
 
-func NewPromptRepository(db *gorm.DB) *PromptRepository {
+func NewPromptRepository(db *gorm.DB, titleCollation string, qualityWeights models.QualityWeights) *PromptRepository {
 	return &PromptRepository{
-		db: db,
+		db:             db,
+		titleCollation: titleCollation,
+		qualityWeights: qualityWeights,
 	}
 }
 
-func (r *PromptRepository) FindAll(filter models.PromptFilter, page, limit int) ([]models.Prompt, int64, error) {
+func (r *PromptRepository) FindAll(filter models.PromptFilter, pagination models.PaginationParams) ([]models.Prompt, int64, error) {
 
 	var prompts []models.Prompt
 	var total int64
@@ -30,10 +41,14 @@ func (r *PromptRepository) FindAll(filter models.PromptFilter, page, limit int)
 		return nil, 0, err
 	}
 
+	// A page past the end is empty, skip the offset scan (?page=1000000 would walk every row)
+	if int64(pagination.Offset()) >= total {
+		return []models.Prompt{}, total, nil
+	}
+
 	// offset pagination
-	offset := (page - 1) * limit
-	if err := query.Offset(offset).Limit(limit).
-		Order("created_at DESC").
+	if err := query.Scopes(withCounts).Offset(pagination.Offset()).Limit(pagination.Limit).
+		Order(r.listOrder(filter)).
 		Find(&prompts).Error; err != nil {
 		return nil, 0, err
 	}
@@ -41,11 +56,71 @@ func (r *PromptRepository) FindAll(filter models.PromptFilter, page, limit int)
 	return prompts, total, nil
 }
 
+// CountVerified counts the live verified prompts, the ones listed in the sitemap
+func (r *PromptRepository) CountVerified() (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Prompt{}).Where("is_verified = ?", true).Count(&total).Error
+	return total, err
+}
+
+// FindVerifiedForSitemap loads only id and updated_at of live verified prompts, in id order
+func (r *PromptRepository) FindVerifiedForSitemap(offset, limit int) ([]models.Prompt, error) {
+	var prompts []models.Prompt
+
+	err := r.db.Model(&models.Prompt{}).
+		Select("id, updated_at").
+		Where("is_verified = ?", true).
+		Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&prompts).Error
+
+	return prompts, err
+}
+
+// Count returns how many prompts match the filter without loading any rows
+func (r *PromptRepository) Count(filter models.PromptFilter) (int64, error) {
+	var total int64
+	err := r.applyFilters(r.db.Model(&models.Prompt{}), filter).Count(&total).Error
+	return total, err
+}
+
 func (r *PromptRepository) FindByID(id uint) (*models.Prompt, error) {
 
 	var prompt models.Prompt
 
-	if err := r.db.First(&prompt, id).Error; err != nil {
+	if err := r.db.Scopes(withCounts).First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("prompt not found")
+		}
+		return nil, err
+	}
+
+	return &prompt, nil
+}
+
+// FindByIDUnscoped is FindByID that also finds soft-deleted prompts
+func (r *PromptRepository) FindByIDUnscoped(id uint) (*models.Prompt, error) {
+
+	var prompt models.Prompt
+
+	if err := r.db.Unscoped().Scopes(withCounts).First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("prompt not found")
+		}
+		return nil, err
+	}
+
+	return &prompt, nil
+}
+
+// FindByExternalID looks up a synced prompt, including soft-deleted ones so an
+// upsert can revive them instead of tripping the unique index
+func (r *PromptRepository) FindByExternalID(externalID string) (*models.Prompt, error) {
+
+	var prompt models.Prompt
+
+	if err := r.db.Unscoped().Where("external_id = ?", externalID).First(&prompt).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("prompt not found")
 		}
@@ -69,6 +144,43 @@ func (r *PromptRepository) Update(prompt *models.Prompt) (*models.Prompt, error)
 	return prompt, nil
 }
 
+// UpdateUnverified saves an edited prompt as unverified and uncounts it on its verifier, in one transaction
+// The audit entry is written in the same transaction
+func (r *PromptRepository) UpdateUnverified(prompt *models.Prompt, audit models.AuditEntry) (*models.Prompt, error) {
+	verifiedBy := prompt.VerifiedBy
+
+	prompt.IsVerified = false
+	prompt.VerifiedBy = nil
+	prompt.VerifiedAt = nil
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(prompt).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&audit).Error; err != nil {
+			return err
+		}
+		if verifiedBy != nil {
+			return tx.Model(&models.User{}).Where("id = ?", *verifiedBy).
+				Update("prompts_verified", gorm.Expr("GREATEST(prompts_verified - 1, 0)")).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// Restore saves a prompt and clears its soft delete in the same write
+func (r *PromptRepository) Restore(prompt *models.Prompt) (*models.Prompt, error) {
+	prompt.DeletedAt = gorm.DeletedAt{}
+	if err := r.db.Unscoped().Save(prompt).Error; err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
 func (r *PromptRepository) Delete(id uint) error {
 	result := r.db.Delete(&models.Prompt{}, id)
 	if result.Error != nil {
@@ -80,16 +192,240 @@ func (r *PromptRepository) Delete(id uint) error {
 	return nil
 }
 
+// MarkVerified verifies a prompt on behalf of verifierID and counts it on the verifier
+// Returns false without changing anything when the prompt is already verified
+func (r *PromptRepository) MarkVerified(promptID, verifierID uint) (bool, error) {
+	changed := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var prompt models.Prompt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prompt, promptID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("prompt not found")
+			}
+			return err
+		}
+		if prompt.IsVerified {
+			return nil
+		}
+
+		if err := tx.Model(&prompt).Updates(map[string]interface{}{
+			"is_verified": true,
+			"verified_by": verifierID,
+			"verified_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", verifierID).
+			Update("prompts_verified", gorm.Expr("prompts_verified + 1")).Error; err != nil {
+			return err
+		}
+
+		changed = true
+		return nil
+	})
+
+	return changed, err
+}
+
+// ClearVerification puts a prompt back to unverified, uncounts it on its verifier and writes the audit entry
+// Returns false without changing anything when the prompt isn't verified
+func (r *PromptRepository) ClearVerification(promptID uint, audit models.AuditEntry) (bool, error) {
+	changed := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var prompt models.Prompt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prompt, promptID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("prompt not found")
+			}
+			return err
+		}
+		if !prompt.IsVerified {
+			return nil
+		}
+
+		if err := tx.Model(&prompt).Updates(map[string]interface{}{
+			"is_verified": false,
+			"verified_by": nil,
+			"verified_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&audit).Error; err != nil {
+			return err
+		}
+		if prompt.VerifiedBy != nil {
+			if err := tx.Model(&models.User{}).Where("id = ?", *prompt.VerifiedBy).
+				Update("prompts_verified", gorm.Expr("GREATEST(prompts_verified - 1, 0)")).Error; err != nil {
+				return err
+			}
+		}
+
+		changed = true
+		return nil
+	})
+
+	return changed, err
+}
+
+// DeleteIfUnchanged soft-deletes a prompt only if it wasn't saved since updatedAt
+// The check and the delete are one statement, so a concurrent edit can't slip in between
+func (r *PromptRepository) DeleteIfUnchanged(id uint, updatedAt time.Time) error {
+	result := r.db.Where("updated_at = ?", updatedAt).Delete(&models.Prompt{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("precondition failed: prompt was modified")
+	}
+	return nil
+}
+
+// IncrementViewCount bumps the view counter with a single upsert so concurrent views don't lose updates
 func (r *PromptRepository) IncrementViewCount(id uint) error {
-	return r.db.Model(&models.Prompt{}).
-		Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1)).Error
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prompt_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"view_count": gorm.Expr("prompt_counts.view_count + ?", 1)}),
+	}).Create(&models.PromptCount{PromptID: id, ViewCount: 1}).Error
+}
+
+// ToggleLike flips userID's like on a prompt and returns the new state and like count
+// The delete-or-insert runs against the (prompt_id, user_id) key, so concurrent toggles
+// serialize on that row and never leave a duplicate like or a double-counted total
+func (r *PromptRepository) ToggleLike(promptID, userID uint) (bool, int, error) {
+	var liked bool
+	var count models.PromptCount
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&models.Prompt{}).Where("id = ?", promptID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return errors.New("prompt not found")
+		}
+
+		removed := tx.Where("prompt_id = ? AND user_id = ?", promptID, userID).Delete(&models.PromptLike{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+
+		delta := -int(removed.RowsAffected)
+		if removed.RowsAffected == 0 {
+			added := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.PromptLike{PromptID: promptID, UserID: userID})
+			if added.Error != nil {
+				return added.Error
+			}
+			delta = int(added.RowsAffected)
+			liked = true
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "prompt_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"like_count": gorm.Expr("GREATEST(prompt_counts.like_count + ?, 0)", delta)}),
+		}).Create(&models.PromptCount{PromptID: promptID, LikeCount: max(delta, 0)}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("prompt_id = ?", promptID).First(&count).Error
+	})
+
+	return liked, count.LikeCount, err
+}
+
+// UpdateTags rewrites the tags of the given prompts in one transaction, rows locked meanwhile
+// edit returns a prompt's new tags, or false to leave it untouched; missing ids are skipped
+func (r *PromptRepository) UpdateTags(ids []uint, edit func(prompt *models.Prompt) (string, bool)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var prompts []models.Prompt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&prompts).Error; err != nil {
+			return err
+		}
+
+		for i := range prompts {
+			tags, changed := edit(&prompts[i])
+			if !changed {
+				continue
+			}
+			if err := tx.Model(&models.Prompt{}).Where("id = ?", prompts[i].ID).Update("tags", tags).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddLike records userID's like on a prompt, a no-op when it's already there, and returns the like count
+// Unlike ToggleLike it never removes the like, so retried requests are safe
+func (r *PromptRepository) AddLike(promptID, userID uint) (int, error) {
+	var count models.PromptCount
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&models.Prompt{}).Where("id = ?", promptID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return errors.New("prompt not found")
+		}
+
+		added := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.PromptLike{PromptID: promptID, UserID: userID})
+		if added.Error != nil {
+			return added.Error
+		}
+
+		if added.RowsAffected > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "prompt_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"like_count": gorm.Expr("prompt_counts.like_count + ?", 1)}),
+			}).Create(&models.PromptCount{PromptID: promptID, LikeCount: 1}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("prompt_id = ?", promptID).First(&count).Error
+	})
+
+	return count.LikeCount, err
+}
+
+// IncrementLikeCount atomically bumps like_count without a prompt_likes row, returns the new count
+// Used for anonymous likes, which have no user to key a like row on
+func (r *PromptRepository) IncrementLikeCount(promptID uint) (int, error) {
+	var count models.PromptCount
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&models.Prompt{}).Where("id = ?", promptID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return errors.New("prompt not found")
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "prompt_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"like_count": gorm.Expr("prompt_counts.like_count + 1")}),
+		}).Create(&models.PromptCount{PromptID: promptID, LikeCount: 1}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("prompt_id = ?", promptID).First(&count).Error
+	})
+
+	return count.LikeCount, err
 }
 
 func (r *PromptRepository) FindByLanguage(language string, limit int) ([]models.Prompt, error) {
 	var prompts []models.Prompt
 
-	err := r.db.Where("language = ?", language).
+	err := r.db.Scopes(withCounts).Where("language = ?", language).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&prompts).Error
@@ -100,7 +436,7 @@ func (r *PromptRepository) FindByLanguage(language string, limit int) ([]models.
 func (r *PromptRepository) FindPopular(limit int) ([]models.Prompt, error) {
 	var prompts []models.Prompt
 
-	err := r.db.Order("view_count DESC").
+	err := r.db.Scopes(withCounts).Order("COALESCE(prompt_counts.view_count, 0) DESC").
 		Limit(limit).
 		Find(&prompts).Error
 
@@ -110,7 +446,7 @@ func (r *PromptRepository) FindPopular(limit int) ([]models.Prompt, error) {
 func (r *PromptRepository) FindByDifficulty(difficulty models.DifficultyLevel, limit int) ([]models.Prompt, error) {
 	var prompts []models.Prompt
 
-	err := r.db.Where("difficulty = ?", difficulty).
+	err := r.db.Scopes(withCounts).Where("difficulty = ?", difficulty).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&prompts).Error
@@ -124,6 +460,339 @@ func (r *PromptRepository) Exists(id uint) (bool, error) {
 	return count > 0, err
 }
 
+// FindTranslations returns the prompt's translations for the given locales, in no particular order
+func (r *PromptRepository) FindTranslations(promptID uint, locales []string) ([]models.PromptTranslation, error) {
+	var translations []models.PromptTranslation
+
+	query := r.db.Where("prompt_id = ?", promptID)
+	if len(locales) > 0 {
+		query = query.Where("locale IN ?", locales)
+	}
+
+	err := query.Order("locale ASC").Find(&translations).Error
+	return translations, err
+}
+
+// UpsertTranslation creates the translation or replaces the content of the existing one for its locale
+func (r *PromptRepository) UpsertTranslation(translation *models.PromptTranslation) (*models.PromptTranslation, error) {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prompt_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "problem_statement", "updated_at", "deleted_at"}),
+	}).Create(translation).Error
+	if err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// MergeInto folds the merged prompts into keepID in one transaction
+// Their view counts are added to the kept prompt, requests completed by them are
+// repointed at it, and the merged prompts are soft-deleted
+// like_count is rebuilt as the kept prompt's prompt_likes rows after the merge plus every
+// prompt's anonymous likes (like_count beyond its rows), which have no user to de-duplicate
+func (r *PromptRepository) MergeInto(keepID uint, mergeIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		allIDs := append([]uint{keepID}, mergeIDs...)
+
+		var found int64
+		if err := tx.Model(&models.Prompt{}).
+			Where("id IN ?", allIDs).
+			Count(&found).Error; err != nil {
+			return err
+		}
+		if found != int64(len(mergeIDs)+1) {
+			return errors.New("prompt not found")
+		}
+
+		// Taken before the likes move, afterwards the rows no longer line up with their prompts
+		var anonymousLikes int64
+		if err := tx.Raw(`SELECT COALESCE(SUM(GREATEST(prompt_counts.like_count - (
+				SELECT COUNT(*) FROM prompt_likes WHERE prompt_likes.prompt_id = prompt_counts.prompt_id
+			), 0)), 0)
+			FROM prompt_counts WHERE prompt_id IN ?`, allIDs).Scan(&anonymousLikes).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO prompt_counts (prompt_id, view_count, like_count)
+			SELECT ?, COALESCE(SUM(view_count), 0), 0
+			FROM prompt_counts WHERE prompt_id IN ?
+			ON CONFLICT (prompt_id) DO UPDATE SET
+				view_count = prompt_counts.view_count + EXCLUDED.view_count`,
+			keepID, mergeIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("prompt_id IN ?", mergeIDs).Delete(&models.PromptCount{}).Error; err != nil {
+			return err
+		}
+
+		// Carry likes over, a user who liked several duplicates keeps a single like
+		if err := tx.Exec(`INSERT INTO prompt_likes (prompt_id, user_id, created_at)
+			SELECT ?, user_id, MIN(created_at) FROM prompt_likes WHERE prompt_id IN ? GROUP BY user_id
+			ON CONFLICT DO NOTHING`, keepID, mergeIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("prompt_id IN ?", mergeIDs).Delete(&models.PromptLike{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`UPDATE prompt_counts
+			SET like_count = ? + (SELECT COUNT(*) FROM prompt_likes WHERE prompt_likes.prompt_id = ?)
+			WHERE prompt_id = ?`, anonymousLikes, keepID, keepID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&models.PromptRequest{}).
+			Where("completed_prompt_id IN ?", mergeIDs).
+			UpdateColumn("completed_prompt_id", keepID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Prompt{}, mergeIDs).Error
+	})
+}
+
+// PurgeDeletedBefore hard-deletes prompts soft-deleted before cutoff
+// Requests pointing at a purged prompt get their CompletedPromptID cleared first
+func (r *PromptRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expiredIDs := tx.Unscoped().Model(&models.Prompt{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+
+		if err := tx.Unscoped().Model(&models.PromptRequest{}).
+			Where("completed_prompt_id IN (?)", expiredIDs).
+			UpdateColumn("completed_prompt_id", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("prompt_id IN (?)", expiredIDs).Delete(&models.PromptCount{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("prompt_id IN (?)", expiredIDs).Delete(&models.PromptLike{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Delete(&models.Prompt{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		purged = result.RowsAffected
+		return nil
+	})
+
+	return purged, err
+}
+
+// LanguageEngagement aggregates prompt count, views, likes and average difficulty per language
+func (r *PromptRepository) LanguageEngagement(sort models.LanguageEngagementSort) ([]models.LanguageEngagement, error) {
+	var stats []models.LanguageEngagement
+
+	order := "total_views DESC, language ASC"
+	switch sort {
+	case models.EngagementSortLikes:
+		order = "total_likes DESC, language ASC"
+	case models.EngagementSortPrompts:
+		order = "prompt_count DESC, language ASC"
+	}
+
+	err := r.db.Model(&models.Prompt{}).
+		Select(`language,
+			COUNT(*) AS prompt_count,
+			COALESCE(SUM(prompt_counts.view_count), 0) AS total_views,
+			COALESCE(SUM(prompt_counts.like_count), 0) AS total_likes,
+			AVG(CASE difficulty WHEN 'beginner' THEN 1 WHEN 'intermediate' THEN 2 WHEN 'advanced' THEN 3 WHEN 'expert' THEN 4 END) AS avg_difficulty`).
+		Joins("LEFT JOIN prompt_counts ON prompt_counts.prompt_id = prompts.id").
+		Group("language").
+		Order(order).
+		Scan(&stats).Error
+
+	return stats, err
+}
+
+// TrendingTags counts tag usage on prompts created since the given time, most used first
+// Tags are compared case-insensitively; rows whose tags aren't a JSON array are skipped
+func (r *PromptRepository) TrendingTags(since time.Time, limit int) ([]models.TagUsage, error) {
+	var tags []models.TagUsage
+
+	err := r.db.Model(&models.Prompt{}).
+		Select("LOWER(TRIM(t.name)) AS tag, COUNT(*) AS prompt_count").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(prompts.tags::jsonb) AS t(name)").
+		Where("prompts.created_at >= ?", since).
+		Where("prompts.tags LIKE '[%]'").
+		Where("TRIM(t.name) <> ''").
+		Group("LOWER(TRIM(t.name))").
+		Order("prompt_count DESC, tag ASC").
+		Limit(limit).
+		Scan(&tags).Error
+
+	return tags, err
+}
+
+// AuthorDifficultyBreakdown counts an author's live prompts per difficulty
+// Every difficulty is present in the result, levels without prompts are 0
+func (r *PromptRepository) AuthorDifficultyBreakdown(authorID uint) (map[models.DifficultyLevel]int64, error) {
+	var rows []struct {
+		Difficulty models.DifficultyLevel
+		Total      int64
+	}
+
+	if err := r.db.Model(&models.Prompt{}).
+		Select("difficulty, COUNT(*) AS total").
+		Where("author_id = ?", authorID).
+		Group("difficulty").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	breakdown := make(map[models.DifficultyLevel]int64, len(models.DifficultyLevels))
+	for _, level := range models.DifficultyLevels {
+		breakdown[level] = 0
+	}
+	for _, row := range rows {
+		breakdown[row.Difficulty] = row.Total
+	}
+
+	return breakdown, nil
+}
+
+// AuthorSummary counts an author's live prompts, verified prompts, and their summed views/likes
+// An author without prompts gets all zeroes
+func (r *PromptRepository) AuthorSummary(authorID uint) (models.AuthorSummary, error) {
+	var summary models.AuthorSummary
+
+	err := r.db.Model(&models.Prompt{}).
+		Select(`COUNT(*) AS prompt_count,
+			COUNT(*) FILTER (WHERE prompts.is_verified) AS verified_count,
+			COALESCE(SUM(prompt_counts.view_count), 0) AS total_views,
+			COALESCE(SUM(prompt_counts.like_count), 0) AS total_likes`).
+		Joins("LEFT JOIN prompt_counts ON prompt_counts.prompt_id = prompts.id").
+		Where("prompts.author_id = ?", authorID).
+		Scan(&summary).Error
+
+	return summary, err
+}
+
+// FindRecentByAuthor returns an author's most recently created or verified prompts
+func (r *PromptRepository) FindRecentByAuthor(authorID uint, limit int) ([]models.Prompt, error) {
+	var prompts []models.Prompt
+
+	err := r.db.Select("id, title, is_verified, verified_at, created_at").
+		Where("author_id = ?", authorID).
+		Order("GREATEST(created_at, COALESCE(verified_at, created_at)) DESC, id DESC").
+		Limit(limit).
+		Find(&prompts).Error
+
+	return prompts, err
+}
+
+// SnapshotCounts copies every prompt's current counters into prompt_count_snapshots, stamped at
+func (r *PromptRepository) SnapshotCounts(at time.Time) (int64, error) {
+	result := r.db.Exec(`INSERT INTO prompt_count_snapshots (prompt_id, view_count, like_count, taken_at)
+		SELECT prompt_id, view_count, like_count, ? FROM prompt_counts`, at)
+	return result.RowsAffected, result.Error
+}
+
+// PruneSnapshotsBefore drops counter snapshots taken before the cutoff
+func (r *PromptRepository) PruneSnapshotsBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("taken_at < ?", cutoff).Delete(&models.PromptCountSnapshot{})
+	return result.RowsAffected, result.Error
+}
+
+// CountDeltas compares the live counters of the given prompts with their latest snapshot taken at or before since
+// Prompts that don't exist (or are unverified, with verifiedOnly) are left out
+func (r *PromptRepository) CountDeltas(ids []uint, since time.Time, verifiedOnly bool) ([]models.PromptCountDelta, error) {
+	var deltas []models.PromptCountDelta
+
+	query := r.db.Model(&models.Prompt{}).
+		Select(`prompts.id AS prompt_id,
+			COALESCE(prompt_counts.view_count, 0) AS view_count,
+			COALESCE(prompt_counts.like_count, 0) AS like_count,
+			COALESCE(prompt_counts.view_count, 0) - COALESCE(baseline.view_count, 0) AS view_delta,
+			COALESCE(prompt_counts.like_count, 0) - COALESCE(baseline.like_count, 0) AS like_delta,
+			baseline.taken_at AS baseline_at`).
+		Joins("LEFT JOIN prompt_counts ON prompt_counts.prompt_id = prompts.id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT view_count, like_count, taken_at FROM prompt_count_snapshots
+			WHERE prompt_count_snapshots.prompt_id = prompts.id AND taken_at <= ?
+			ORDER BY taken_at DESC LIMIT 1
+		) AS baseline ON TRUE`, since).
+		Where("prompts.id IN ?", ids)
+
+	if verifiedOnly {
+		query = query.Where("prompts.is_verified = ?", true)
+	}
+
+	err := query.Order("prompts.id ASC").Scan(&deltas).Error
+	return deltas, err
+}
+
+// withCounts joins the engagement counters from prompt_counts onto prompt reads
+func withCounts(db *gorm.DB) *gorm.DB {
+	return db.Select("prompts.*, COALESCE(prompt_counts.view_count, 0) AS view_count, COALESCE(prompt_counts.like_count, 0) AS like_count").
+		Joins("LEFT JOIN prompt_counts ON prompt_counts.prompt_id = prompts.id")
+}
+
+// listOrder picks the seeded order when a seed is given, the requested sort otherwise
+func (r *PromptRepository) listOrder(filter models.PromptFilter) interface{} {
+	if filter.Seed != "" {
+		return seededOrder(filter.Seed)
+	}
+	return r.sortOrder(filter.Sort)
+}
+
+// seededOrder shuffles by md5(seed || id), the same seed always gives the same order
+// id breaks the (practically impossible) hash ties so pages never overlap
+func seededOrder(seed string) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "md5(? || prompts.id::text), prompts.id",
+		Vars:               []interface{}{seed},
+		WithoutParentheses: true,
+	}}
+}
+
+func (r *PromptRepository) sortOrder(sort models.PromptSort) interface{} {
+	switch sort {
+	case models.SortQuality:
+		return r.qualityOrder()
+	case models.SortTitle:
+		if r.titleCollation != "" {
+			return fmt.Sprintf(`title COLLATE "%s" ASC, id ASC`, r.titleCollation)
+		}
+		return "LOWER(title) ASC, title ASC, id ASC"
+	case models.SortOldest:
+		return "created_at ASC"
+	case models.SortPopular:
+		return "COALESCE(prompt_counts.view_count, 0) DESC, created_at DESC"
+	case models.SortVerifiedFirst:
+		return "is_verified DESC, created_at DESC"
+	}
+	return "created_at DESC"
+}
+
+// qualityOrder is the SQL version of the service's quality score, highest first
+func (r *PromptRepository) qualityOrder() clause.OrderBy {
+	w := r.qualityWeights
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL: `(? * (CASE WHEN is_verified THEN 1 ELSE 0 END)
+			+ ? * LEAST(COALESCE(prompt_counts.like_count, 0)::float / GREATEST(COALESCE(prompt_counts.view_count, 0), 1), 1)
+			+ ? * ((CASE WHEN TRIM(examples) <> '' THEN 1 ELSE 0 END)
+				+ (CASE WHEN TRIM(hints) <> '' THEN 1 ELSE 0 END))::float / 2
+			+ ? / (1 + EXTRACT(EPOCH FROM (NOW() - prompts.created_at)) / 86400 / ?)) DESC, prompts.created_at DESC`,
+		Vars: []interface{}{
+			w.Verified, w.Engagement, w.Completeness,
+			w.Freshness, models.QualityFreshnessDays,
+		},
+		WithoutParentheses: true,
+	}}
+}
+
 func (r *PromptRepository) applyFilters(query *gorm.DB, filter models.PromptFilter) *gorm.DB {
 	if filter.Language != "" {
 		query = query.Where("language = ?", filter.Language)
@@ -135,9 +804,20 @@ func (r *PromptRepository) applyFilters(query *gorm.DB, filter models.PromptFilt
 		query = query.Where("category = ?", filter.Category)
 	}
 
+	if len(filter.IDs) > 0 {
+		query = query.Where("prompts.id IN ?", filter.IDs)
+	}
+
 	if filter.IsVerified != nil {
 		query = query.Where("is_verified = ?", *filter.IsVerified)
 	}
+	if filter.VerifiedOnly {
+		query = query.Where("is_verified = ?", true)
+	}
+
+	if filter.AuthorID != nil {
+		query = query.Where("author_id = ?", *filter.AuthorID)
+	}
 
 	if filter.Search != "" {
 		searchTerm := "%" + strings.ToLower(filter.Search) + "%"
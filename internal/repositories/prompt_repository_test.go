@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"PromptGallery/internal/database"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/testdb"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestPromptRepository(t *testing.T) *PromptRepository {
+	t.Helper()
+	return NewPromptRepository(testdb.Open(t), "", models.QualityWeights{})
+}
+
+func TestPurgeDeletedBefore(t *testing.T) {
+	repo := newTestPromptRepository(t)
+	db := repo.db
+
+	now := time.Now()
+	old := testdb.SeedPrompt(t, db, nil)
+	recent := testdb.SeedPrompt(t, db, nil)
+	live := testdb.SeedPrompt(t, db, nil)
+	testdb.SetCounts(t, db, old.ID, 5, 1)
+
+	db.Unscoped().Model(old).UpdateColumn("deleted_at", now.Add(-100*24*time.Hour))
+	db.Unscoped().Model(recent).UpdateColumn("deleted_at", now.Add(-10*24*time.Hour))
+
+	request := testdb.SeedRequest(t, db, func(r *models.PromptRequest) {
+		r.Status = models.StatusCompleted
+		r.CompletedPromptID = &old.ID
+	})
+
+	purged, err := repo.PurgeDeletedBefore(now.Add(-90 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeDeletedBefore: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged = %d, want 1", purged)
+	}
+
+	if _, err := repo.FindByIDUnscoped(old.ID); err == nil {
+		t.Error("prompt deleted 100 days ago is still stored")
+	}
+	if _, err := repo.FindByIDUnscoped(recent.ID); err != nil {
+		t.Errorf("prompt deleted 10 days ago was purged: %v", err)
+	}
+	if _, err := repo.FindByID(live.ID); err != nil {
+		t.Errorf("live prompt was purged: %v", err)
+	}
+
+	var counts int64
+	db.Model(&models.PromptCount{}).Where("prompt_id = ?", old.ID).Count(&counts)
+	if counts != 0 {
+		t.Error("counters of the purged prompt are still stored")
+	}
+
+	var reloaded models.PromptRequest
+	db.First(&reloaded, request.ID)
+	if reloaded.CompletedPromptID != nil {
+		t.Errorf("request still points at purged prompt %d", *reloaded.CompletedPromptID)
+	}
+}
+
+func TestConcurrentIncrementsKeepEveryUpdate(t *testing.T) {
+	repo := newTestPromptRepository(t)
+	prompt := testdb.SeedPrompt(t, repo.db, nil)
+
+	const workers, perWorker = 8, 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker*2)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				if err := repo.IncrementViewCount(prompt.ID); err != nil {
+					errs <- err
+				}
+				if _, err := repo.IncrementLikeCount(prompt.ID); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("increment failed: %v", err)
+	}
+
+	stored, err := repo.FindByID(prompt.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if stored.ViewCount != workers*perWorker || stored.LikeCount != workers*perWorker {
+		t.Errorf("counts = %d views, %d likes, want %d of each", stored.ViewCount, stored.LikeCount, workers*perWorker)
+	}
+}
+
+func TestMigrateCopiesLegacyCountsOnce(t *testing.T) {
+	db := testdb.Open(t)
+	prompt := testdb.SeedPrompt(t, db, nil)
+
+	// Put back the columns counters lived in before prompt_counts
+	if err := db.Exec("ALTER TABLE prompts ADD COLUMN view_count bigint DEFAULT 0, ADD COLUMN like_count bigint DEFAULT 0").Error; err != nil {
+		t.Fatalf("add legacy columns: %v", err)
+	}
+	db.Exec("UPDATE prompts SET view_count = 7, like_count = 3 WHERE id = ?", prompt.ID)
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if db.Migrator().HasColumn("prompts", "view_count") || db.Migrator().HasColumn("prompts", "like_count") {
+		t.Error("legacy counter columns still on prompts")
+	}
+
+	var counts models.PromptCount
+	db.First(&counts, "prompt_id = ?", prompt.ID)
+	if counts.ViewCount != 7 || counts.LikeCount != 3 {
+		t.Errorf("copied counts = %d views, %d likes, want 7 and 3", counts.ViewCount, counts.LikeCount)
+	}
+
+	// A second boot doesn't bring back a row removed since
+	db.Delete(&models.PromptCount{}, "prompt_id = ?", prompt.ID)
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Migrate again: %v", err)
+	}
+	var rows int64
+	db.Model(&models.PromptCount{}).Where("prompt_id = ?", prompt.ID).Count(&rows)
+	if rows != 0 {
+		t.Errorf("%d prompt_counts rows after the second migration, want 0", rows)
+	}
+}
@@ -1 +1,202 @@
 package repositories
+
+import (
+	"PromptGallery/internal/models"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+type PromptRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptRequestRepository(db *gorm.DB) *PromptRequestRepository {
+	return &PromptRequestRepository{
+		db: db,
+	}
+}
+
+func (r *PromptRequestRepository) Create(request *models.PromptRequest) (*models.PromptRequest, error) {
+	if err := r.db.Create(request).Error; err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (r *PromptRequestRepository) FindByID(id uint) (*models.PromptRequest, error) {
+	var request models.PromptRequest
+
+	if err := r.db.First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("request not found")
+		}
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func (r *PromptRequestRepository) Update(request *models.PromptRequest) (*models.PromptRequest, error) {
+	if err := r.db.Save(request).Error; err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// FindAll pages through requests matching the filter, urgent and newest first
+func (r *PromptRequestRepository) FindAll(filter models.RequestFilter, pagination models.PaginationParams) ([]models.PromptRequest, int64, error) {
+	var requests []models.PromptRequest
+	var total int64
+
+	query := r.applyFilters(r.db.Model(&models.PromptRequest{}), filter)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if int64(pagination.Offset()) >= total {
+		return []models.PromptRequest{}, total, nil
+	}
+
+	err := query.Order("is_urgent DESC, created_at DESC, id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&requests).Error
+
+	return requests, total, err
+}
+
+func (r *PromptRequestRepository) applyFilters(query *gorm.DB, filter models.RequestFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.RequestedLanguage != "" {
+		query = query.Where("requested_language = ?", filter.RequestedLanguage)
+	}
+	if filter.RequestedDifficulty != "" {
+		query = query.Where("requested_difficulty = ?", filter.RequestedDifficulty)
+	}
+	if filter.RequestedCategory != "" {
+		query = query.Where("requested_category = ?", filter.RequestedCategory)
+	}
+
+	if filter.IsUrgent != nil {
+		query = query.Where("is_urgent = ?", *filter.IsUrgent)
+	}
+	if filter.IsRejected != nil {
+		query = query.Where("is_rejected = ?", *filter.IsRejected)
+	}
+	if filter.AssignedToID != nil {
+		query = query.Where("assigned_to_id = ?", *filter.AssignedToID)
+	}
+	if filter.RequesterEmail != "" {
+		query = query.Where("requester_email = ?", models.NormalizeEmail(filter.RequesterEmail))
+	}
+
+	if filter.Search != "" {
+		searchTerm := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(requested_title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+	}
+
+	return query
+}
+
+// FindCompleted pages through completed requests, most recently completed first
+func (r *PromptRequestRepository) FindCompleted(pagination models.PaginationParams) ([]models.PromptRequest, int64, error) {
+	var requests []models.PromptRequest
+	var total int64
+
+	query := r.db.Model(&models.PromptRequest{}).Where("status = ?", models.StatusCompleted)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if int64(pagination.Offset()) >= total {
+		return []models.PromptRequest{}, total, nil
+	}
+
+	err := query.Order("completed_at DESC NULLS LAST, id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&requests).Error
+
+	return requests, total, err
+}
+
+// CountCompletedBy counts the requests a user was assigned and completed
+func (r *PromptRequestRepository) CountCompletedBy(userID uint) (int64, error) {
+	var total int64
+
+	err := r.db.Model(&models.PromptRequest{}).
+		Where("assigned_to_id = ? AND status = ?", userID, models.StatusCompleted).
+		Count(&total).Error
+
+	return total, err
+}
+
+// FindCompletedBy returns a user's most recently completed requests
+func (r *PromptRequestRepository) FindCompletedBy(userID uint, limit int) ([]models.PromptRequest, error) {
+	var requests []models.PromptRequest
+
+	err := r.db.Where("assigned_to_id = ? AND status = ?", userID, models.StatusCompleted).
+		Order("completed_at DESC NULLS LAST, id DESC").
+		Limit(limit).
+		Find(&requests).Error
+
+	return requests, err
+}
+
+// FindByCompletedPrompt returns the requests fulfilled by a prompt, oldest first
+func (r *PromptRequestRepository) FindByCompletedPrompt(promptID uint) ([]models.PromptRequest, error) {
+	var requests []models.PromptRequest
+
+	err := r.db.Where("completed_prompt_id = ?", promptID).
+		Order("id ASC").
+		Find(&requests).Error
+
+	return requests, err
+}
+
+// SetPriority moves the listed requests to priority in one transaction, auditing each change under actorID
+// Returns the requests that exist as they were before the change
+func (r *PromptRequestRepository) SetPriority(ids []uint, priority models.Priority, actorID uint) ([]models.PromptRequest, error) {
+	var requests []models.PromptRequest
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&requests).Error; err != nil {
+			return err
+		}
+
+		var entries []models.AuditEntry
+		for _, request := range requests {
+			if request.Priority != priority {
+				entries = append(entries, models.AuditEntry{
+					ActorID:  actorID,
+					Action:   models.AuditActionRequestPriority,
+					TargetID: request.ID,
+					OldValue: string(request.Priority),
+					NewValue: string(priority),
+				})
+			}
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.PromptRequest{}).
+			Where("id IN ? AND priority <> ?", ids, priority).
+			Update("priority", priority).Error
+	})
+
+	return requests, err
+}
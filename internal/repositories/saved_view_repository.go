@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"PromptGallery/internal/models"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type SavedViewRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedViewRepository(db *gorm.DB) *SavedViewRepository {
+	return &SavedViewRepository{
+		db: db,
+	}
+}
+
+func (r *SavedViewRepository) FindByUser(userID uint) ([]models.SavedView, error) {
+	var views []models.SavedView
+
+	err := r.db.Where("user_id = ?", userID).
+		Order("name ASC, id ASC").
+		Find(&views).Error
+
+	return views, err
+}
+
+// FindForUser loads a view only if it belongs to userID, other users' views are "not found"
+func (r *SavedViewRepository) FindForUser(id, userID uint) (*models.SavedView, error) {
+
+	var view models.SavedView
+
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("view not found")
+		}
+		return nil, err
+	}
+
+	return &view, nil
+}
+
+func (r *SavedViewRepository) NameTaken(userID uint, name string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.SavedView{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *SavedViewRepository) Create(view *models.SavedView) (*models.SavedView, error) {
+	if err := r.db.Create(view).Error; err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (r *SavedViewRepository) Update(view *models.SavedView) (*models.SavedView, error) {
+	if err := r.db.Save(view).Error; err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (r *SavedViewRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.SavedView{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("view not found")
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"PromptGallery/internal/models"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{
+		db: db,
+	}
+}
+
+// Create inserts a user, a taken email or username comes back as "... already exists"
+func (r *UserRepository) Create(user *models.User) (*models.User, error) {
+	if err := r.db.Create(user).Error; err != nil {
+		return nil, duplicateUserError(err)
+	}
+	return user, nil
+}
+
+// Update saves every field of the user, same duplicate handling as Create
+func (r *UserRepository) Update(user *models.User) (*models.User, error) {
+	if err := r.db.Save(user).Error; err != nil {
+		return nil, duplicateUserError(err)
+	}
+	return user, nil
+}
+
+// duplicateUserError names the column behind a unique violation on users
+// The unique indexes are the source of truth, so two concurrent signups can't both get through
+// Soft-deleted users keep their email and username taken
+func duplicateUserError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	if strings.Contains(pgErr.ConstraintName, "username") {
+		return errors.New("username already exists")
+	}
+	if strings.Contains(pgErr.ConstraintName, "email") {
+		return errors.New("email already exists")
+	}
+	return errors.New("user already exists")
+}
+
+// FindByEmail loads a live user by email, matched case-insensitively like the unique index
+func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
+
+	var user models.User
+
+	if err := r.db.Where("email = ?", models.NormalizeEmail(email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CreateToken stores a new API token
+func (r *UserRepository) CreateToken(token *models.APIToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByTokenHash loads the live, active user owning a token that hasn't expired by now
+func (r *UserRepository) FindByTokenHash(hash string, now time.Time) (*models.User, error) {
+
+	var user models.User
+
+	if err := r.db.Select("users.*").
+		Joins("JOIN api_tokens ON api_tokens.user_id = users.id AND api_tokens.deleted_at IS NULL").
+		Where("api_tokens.token_hash = ? AND api_tokens.expires_at > ? AND users.is_active", hash, now).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteToken revokes a token, false when no live token had that hash
+func (r *UserRepository) DeleteToken(hash string) (bool, error) {
+	result := r.db.Where("token_hash = ?", hash).Delete(&models.APIToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindAll pages through users matching the filter, oldest accounts first
+func (r *UserRepository) FindAll(filter models.UserFilter, pagination models.PaginationParams) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Search != "" {
+		searchTerm := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(username) LIKE ?", searchTerm, searchTerm)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if int64(pagination.Offset()) >= total {
+		return []models.User{}, total, nil
+	}
+
+	err := query.Order("created_at ASC, id ASC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Find(&users).Error
+
+	return users, total, err
+}
+
+func (r *UserRepository) FindByUsername(username string) (*models.User, error) {
+
+	var user models.User
+
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// FindByIDs loads the users with the given ids in one query, missing ids are simply absent
+func (r *UserRepository) FindByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// BackfillPromptsCreated recomputes every user's PromptsCreated from their live prompts
+// One UPDATE ... FROM, only rows whose counter actually changes are written
+func (r *UserRepository) BackfillPromptsCreated() (int64, error) {
+	result := r.db.Exec(`UPDATE users SET prompts_created = counts.total
+		FROM (
+			SELECT users.id, COUNT(prompts.id) AS total
+			FROM users
+			LEFT JOIN prompts ON prompts.author_id = users.id AND prompts.deleted_at IS NULL
+			GROUP BY users.id
+		) AS counts
+		WHERE counts.id = users.id AND users.prompts_created IS DISTINCT FROM counts.total`)
+
+	return result.RowsAffected, result.Error
+}
+
+// FindByID loads a live user
+func (r *UserRepository) FindByID(id uint) (*models.User, error) {
+
+	var user models.User
+
+	if err := r.db.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// MergeInto folds mergeID into keepID in one transaction
+// Authored and verified prompts, likes, saved views and request assignments move to the kept
+// user, the stat counters are summed, and the merged account is soft-deleted
+func (r *UserRepository) MergeInto(keepID, mergeID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Model(&models.User{}).Where("id IN ?", []uint{keepID, mergeID}).Count(&found).Error; err != nil {
+			return err
+		}
+		if found != 2 {
+			return errors.New("user not found")
+		}
+
+		// Soft-deleted prompts move too, so a restore keeps the right author
+		if err := tx.Unscoped().Model(&models.Prompt{}).
+			Where("author_id = ?", mergeID).
+			UpdateColumn("author_id", keepID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Prompt{}).
+			Where("verified_by = ?", mergeID).
+			UpdateColumn("verified_by", keepID).Error; err != nil {
+			return err
+		}
+
+		// A prompt liked from both accounts keeps a single like, so its counter loses one
+		if err := tx.Exec(`UPDATE prompt_counts SET like_count = GREATEST(like_count - 1, 0)
+			WHERE prompt_id IN (
+				SELECT prompt_id FROM prompt_likes WHERE user_id = ?
+				INTERSECT
+				SELECT prompt_id FROM prompt_likes WHERE user_id = ?
+			)`, keepID, mergeID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO prompt_likes (prompt_id, user_id, created_at)
+			SELECT prompt_id, ?, created_at FROM prompt_likes WHERE user_id = ?
+			ON CONFLICT DO NOTHING`, keepID, mergeID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", mergeID).Delete(&models.PromptLike{}).Error; err != nil {
+			return err
+		}
+
+		// View names are unique per user, so a clashing name gets the view id appended
+		if err := tx.Exec(`UPDATE saved_views SET user_id = ?,
+				name = CASE WHEN EXISTS (
+					SELECT 1 FROM saved_views kept
+					WHERE kept.user_id = ? AND kept.deleted_at IS NULL AND LOWER(kept.name) = LOWER(saved_views.name)
+				) THEN LEFT(saved_views.name, 88) || ' (' || saved_views.id || ')' ELSE saved_views.name END
+			WHERE user_id = ?`, keepID, keepID, mergeID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&models.PromptRequest{}).
+			Where("assigned_to_id = ?", mergeID).
+			UpdateColumn("assigned_to_id", keepID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.PromptRequest{}).
+			Where("assigned_by = ?", mergeID).
+			UpdateColumn("assigned_by", keepID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`UPDATE users SET
+				prompts_created = users.prompts_created + merged.prompts_created,
+				prompts_verified = users.prompts_verified + merged.prompts_verified,
+				requests_handled = users.requests_handled + merged.requests_handled
+			FROM users AS merged
+			WHERE users.id = ? AND merged.id = ?`, keepID, mergeID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.User{}, mergeID).Error
+	})
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/testdb"
+	"testing"
+	"time"
+)
+
+func TestMergeInto(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewUserRepository(db)
+
+	keep := testdb.SeedUser(t, db, "keep", func(u *models.User) {
+		u.PromptsCreated, u.PromptsVerified, u.RequestsHandled = 2, 1, 3
+	})
+	merge := testdb.SeedUser(t, db, "merge", func(u *models.User) {
+		u.PromptsCreated, u.PromptsVerified, u.RequestsHandled = 1, 4, 2
+	})
+	bystander := testdb.SeedUser(t, db, "bystander", nil)
+
+	authored := testdb.SeedPrompt(t, db, func(p *models.Prompt) { p.AuthorID = &merge.ID })
+	deleted := testdb.SeedPrompt(t, db, func(p *models.Prompt) { p.AuthorID = &merge.ID })
+	db.Delete(deleted)
+	now := time.Now()
+	verified := testdb.SeedPrompt(t, db, func(p *models.Prompt) {
+		p.AuthorID = &bystander.ID
+		p.IsVerified, p.VerifiedBy, p.VerifiedAt = true, &merge.ID, &now
+	})
+
+	// Both accounts like one prompt, only the merged one likes the other
+	likedByBoth := testdb.SeedPrompt(t, db, nil)
+	likedByMerged := testdb.SeedPrompt(t, db, nil)
+	db.Create(&[]models.PromptLike{
+		{PromptID: likedByBoth.ID, UserID: keep.ID},
+		{PromptID: likedByBoth.ID, UserID: merge.ID},
+		{PromptID: likedByMerged.ID, UserID: merge.ID},
+	})
+	testdb.SetCounts(t, db, likedByBoth.ID, 0, 2)
+	testdb.SetCounts(t, db, likedByMerged.ID, 0, 1)
+
+	db.Create(&[]models.SavedView{
+		{UserID: keep.ID, Name: "Go drills", Filter: "{}"},
+		{UserID: merge.ID, Name: "go drills", Filter: "{}"},
+		{UserID: merge.ID, Name: "Hard ones", Filter: "{}"},
+	})
+
+	request := testdb.SeedRequest(t, db, func(r *models.PromptRequest) {
+		r.Status = models.StatusAssigned
+		r.AssignedToID, r.AssignedBy = &merge.ID, &merge.ID
+	})
+
+	if err := repo.MergeInto(keep.ID, merge.ID); err != nil {
+		t.Fatalf("MergeInto: %v", err)
+	}
+
+	t.Run("authorship moves", func(t *testing.T) {
+		for _, prompt := range []*models.Prompt{authored, deleted} {
+			var stored models.Prompt
+			db.Unscoped().First(&stored, prompt.ID)
+			if stored.AuthorID == nil || *stored.AuthorID != keep.ID {
+				t.Errorf("prompt %d author = %v, want %d", prompt.ID, stored.AuthorID, keep.ID)
+			}
+		}
+
+		var stored models.Prompt
+		db.First(&stored, verified.ID)
+		if stored.VerifiedBy == nil || *stored.VerifiedBy != keep.ID {
+			t.Errorf("verified_by = %v, want %d", stored.VerifiedBy, keep.ID)
+		}
+		if stored.AuthorID == nil || *stored.AuthorID != bystander.ID {
+			t.Errorf("another user's prompt changed author to %v", stored.AuthorID)
+		}
+	})
+
+	t.Run("counters are summed", func(t *testing.T) {
+		var stored models.User
+		db.First(&stored, keep.ID)
+		if stored.PromptsCreated != 3 || stored.PromptsVerified != 5 || stored.RequestsHandled != 5 {
+			t.Errorf("counters = %d created, %d verified, %d handled, want 3, 5, 5",
+				stored.PromptsCreated, stored.PromptsVerified, stored.RequestsHandled)
+		}
+	})
+
+	t.Run("likes are deduplicated", func(t *testing.T) {
+		var likes []models.PromptLike
+		db.Order("prompt_id").Find(&likes)
+		if len(likes) != 2 || likes[0].UserID != keep.ID || likes[1].UserID != keep.ID {
+			t.Errorf("likes = %+v, want one per prompt, all from user %d", likes, keep.ID)
+		}
+
+		for id, want := range map[uint]int{likedByBoth.ID: 1, likedByMerged.ID: 1} {
+			var counts models.PromptCount
+			db.First(&counts, "prompt_id = ?", id)
+			if counts.LikeCount != want {
+				t.Errorf("prompt %d like_count = %d, want %d", id, counts.LikeCount, want)
+			}
+		}
+	})
+
+	t.Run("saved views move, clashing names are renamed", func(t *testing.T) {
+		var views []models.SavedView
+		db.Where("user_id = ?", keep.ID).Order("id").Find(&views)
+		if len(views) != 3 {
+			t.Fatalf("kept user has %d views, want 3", len(views))
+		}
+		if views[0].Name != "Go drills" || views[1].Name == "go drills" || views[2].Name != "Hard ones" {
+			t.Errorf("view names = %q, %q, %q, want the clashing one renamed", views[0].Name, views[1].Name, views[2].Name)
+		}
+	})
+
+	t.Run("request assignments move", func(t *testing.T) {
+		var stored models.PromptRequest
+		db.First(&stored, request.ID)
+		if stored.AssignedToID == nil || *stored.AssignedToID != keep.ID || stored.AssignedBy == nil || *stored.AssignedBy != keep.ID {
+			t.Errorf("assigned to %v by %v, want %d for both", stored.AssignedToID, stored.AssignedBy, keep.ID)
+		}
+	})
+
+	t.Run("merged account is soft-deleted", func(t *testing.T) {
+		var live, all int64
+		db.Model(&models.User{}).Where("id = ?", merge.ID).Count(&live)
+		db.Unscoped().Model(&models.User{}).Where("id = ?", merge.ID).Count(&all)
+		if live != 0 || all != 1 {
+			t.Errorf("merged user: %d live rows, %d rows in total, want 0 and 1", live, all)
+		}
+	})
+}
+
+func TestMergeIntoMissingUser(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewUserRepository(db)
+	keep := testdb.SeedUser(t, db, "keep", nil)
+
+	if err := repo.MergeInto(keep.ID, keep.ID+1000); err == nil || err.Error() != "user not found" {
+		t.Errorf("error = %v, want user not found", err)
+	}
+}
@@ -0,0 +1,23 @@
+package services
+
+// BulkResult is the per-id outcome shared by every bulk endpoint
+// Status is updated, unchanged, not_found or failed; Value is the field the call set, as stored afterwards
+type BulkResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Value  string `json:"value,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// orderBulkResults lists results in the order the ids were asked for, ids with no result are not_found
+func orderBulkResults(ids []uint, results map[uint]BulkResult) []BulkResult {
+	ordered := make([]BulkResult, 0, len(ids))
+	for _, id := range ids {
+		result, ok := results[id]
+		if !ok {
+			result = BulkResult{ID: id, Status: "not_found"}
+		}
+		ordered = append(ordered, result)
+	}
+	return ordered
+}
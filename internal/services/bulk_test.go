@@ -0,0 +1,31 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderBulkResults(t *testing.T) {
+	results := map[uint]BulkResult{
+		3: {ID: 3, Status: "updated", Value: "high"},
+		1: {ID: 1, Status: "unchanged", Value: "high"},
+		9: {ID: 9, Status: "updated", Value: "high"}, // not asked for, dropped
+	}
+
+	got := orderBulkResults([]uint{1, 2, 3}, results)
+	want := []BulkResult{
+		{ID: 1, Status: "unchanged", Value: "high"},
+		{ID: 2, Status: "not_found"},
+		{ID: 3, Status: "updated", Value: "high"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("orderBulkResults = %+v, want %+v", got, want)
+	}
+}
+
+func TestOrderBulkResultsEmpty(t *testing.T) {
+	got := orderBulkResults([]uint{4, 5}, nil)
+	if len(got) != 2 || got[0].Status != "not_found" || got[1].Status != "not_found" {
+		t.Errorf("orderBulkResults = %+v, want two not_found entries", got)
+	}
+}
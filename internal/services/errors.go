@@ -0,0 +1,21 @@
+package services
+
+import "errors"
+
+// Sign-in and token errors, compared with errors.Is by the auth middleware and handlers
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrTooManyAttempts    = errors.New("too many failed sign-in attempts")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// ValidationError is returned when input breaks a business rule
+// Handlers answer it with a 400 and a field-level error
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// failedLogins counts failed sign-ins per key (the client IP) in fixed windows
+// Like recentLikes it lives in process memory, so each instance throttles on its own
+type failedLogins struct {
+	mu        sync.Mutex
+	windows   map[string]loginWindow
+	lastPrune time.Time
+}
+
+type loginWindow struct {
+	start    time.Time
+	failures int
+}
+
+func newFailedLogins() *failedLogins {
+	return &failedLogins{windows: make(map[string]loginWindow)}
+}
+
+// blocked reports how long key must wait when it already failed max times in the current window
+func (f *failedLogins) blocked(key string, max int, window time.Duration, now time.Time) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w, ok := f.windows[key]
+	if !ok || now.Sub(w.start) >= window || w.failures < max {
+		return 0, false
+	}
+	return w.start.Add(window).Sub(now), true
+}
+
+// fail counts one failed sign-in for key
+func (f *failedLogins) fail(key string, window time.Duration, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Drop finished windows once per window so the map doesn't grow with every IP ever seen
+	if now.Sub(f.lastPrune) >= window {
+		for k, w := range f.windows {
+			if now.Sub(w.start) >= window {
+				delete(f.windows, k)
+			}
+		}
+		f.lastPrune = now
+	}
+
+	w, ok := f.windows[key]
+	if !ok || now.Sub(w.start) >= window {
+		w = loginWindow{start: now}
+	}
+	w.failures++
+	f.windows[key] = w
+}
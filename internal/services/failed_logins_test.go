@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFailedLogins(t *testing.T) {
+	f := newFailedLogins()
+	window := 15 * time.Minute
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if _, blocked := f.blocked("10.0.0.1", 3, window, start); blocked {
+			t.Fatalf("blocked after %d failures, limit is 3", i)
+		}
+		f.fail("10.0.0.1", window, start)
+	}
+
+	wait, blocked := f.blocked("10.0.0.1", 3, window, start.Add(5*time.Minute))
+	if !blocked || wait != 10*time.Minute {
+		t.Errorf("after 3 failures: blocked %v, wait %v, want blocked for 10m", blocked, wait)
+	}
+	if _, blocked := f.blocked("10.0.0.2", 3, window, start); blocked {
+		t.Error("another IP was blocked")
+	}
+
+	if _, blocked := f.blocked("10.0.0.1", 3, window, start.Add(window)); blocked {
+		t.Error("still blocked once the window ended")
+	}
+	f.fail("10.0.0.1", window, start.Add(window))
+	if _, blocked := f.blocked("10.0.0.1", 3, window, start.Add(window)); blocked {
+		t.Error("a failure in the new window counted the old ones too")
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"PromptGallery/internal/testdb"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// testConfig holds the LoadConfig defaults the services read
+func testConfig() *config.Config {
+	return &config.Config{
+		Environment:                "test",
+		DefaultPromptSort:          "newest",
+		MinRoleToViewUnverified:    models.RoleAnonymous,
+		AnonymousLikeWindowSeconds: 3600,
+		APITokenTTLHours:           720,
+		LoginMaxFailures:           5,
+		LoginFailureWindowSeconds:  900,
+		MaxDescriptionLength:       5000,
+		MaxProblemStatementLength:  20000,
+		MaxTagsPerPrompt:           10,
+		MinSearchLength:            2,
+		DefaultAuthorName:          "Community",
+		BcryptCost:                 4,
+	}
+}
+
+// testServices wires every service over one test database
+type testServices struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	prompts  *PromptService
+	users    *UserService
+	requests *PromptRequestService
+}
+
+func newTestServices(t *testing.T, cfg *config.Config) *testServices {
+	t.Helper()
+
+	db := testdb.Open(t)
+	promptRepo := repositories.NewPromptRepository(db, cfg.TitleCollation, cfg.QualityWeights)
+	userRepo := repositories.NewUserRepository(db)
+	requestRepo := repositories.NewPromptRequestRepository(db)
+
+	return &testServices{
+		db:       db,
+		cfg:      cfg,
+		prompts:  NewPromptService(promptRepo, userRepo, cfg, nil),
+		users:    NewUserService(userRepo, promptRepo, requestRepo, cfg),
+		requests: NewPromptRequestService(requestRepo),
+	}
+}
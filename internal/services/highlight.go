@@ -0,0 +1,86 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightOpen    = "<mark>"
+	highlightClose   = "</mark>"
+	highlightContext = 60 // Runes kept either side of the match in long fields
+)
+
+// searchHighlight marks the search term in every prompt field it matched, keyed by field name
+// Fields without a match are left out, nil when nothing matched
+func searchHighlight(prompt *models.Prompt, term string) map[string]string {
+	highlight := make(map[string]string)
+
+	fields := []struct {
+		name    string
+		text    string
+		context int
+	}{
+		{"title", prompt.Title, 0},
+		{"description", prompt.Description, highlightContext},
+		{"problem_statement", prompt.ProblemStatement, highlightContext},
+	}
+	for _, field := range fields {
+		if snippet := highlightMatch(field.text, term, field.context); snippet != "" {
+			highlight[field.name] = snippet
+		}
+	}
+
+	if len(highlight) == 0 {
+		return nil
+	}
+	return highlight
+}
+
+// highlightMatch wraps the first case-insensitive match of term in <mark> tags
+// With context > 0 the text is cut to that many runes around the match, elided with "…"
+// Everything outside the markers is HTML-escaped. Returns "" when term doesn't occur
+func highlightMatch(text, term string, context int) string {
+	runes := []rune(text)
+	needle := []rune(strings.ToLower(term))
+	if len(needle) == 0 || len(needle) > len(runes) {
+		return ""
+	}
+
+	idx := -1
+	for i := 0; i+len(needle) <= len(runes) && idx < 0; i++ {
+		idx = i
+		for j, r := range needle {
+			if unicode.ToLower(runes[i+j]) != r {
+				idx = -1
+				break
+			}
+		}
+	}
+	if idx < 0 {
+		return ""
+	}
+	matchEnd := idx + len(needle)
+
+	start, end := 0, len(runes)
+	if context > 0 {
+		start = max(idx-context, 0)
+		end = min(matchEnd+context, len(runes))
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(html.EscapeString(string(runes[start:idx])))
+	b.WriteString(highlightOpen)
+	b.WriteString(html.EscapeString(string(runes[idx:matchEnd])))
+	b.WriteString(highlightClose)
+	b.WriteString(html.EscapeString(string(runes[matchEnd:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestHighlightMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		term    string
+		context int
+		want    string
+	}{
+		{name: "case-insensitive", text: "Two Sum", term: "sum", want: "Two <mark>Sum</mark>"},
+		{name: "first match only", text: "sum of sums", term: "sum", want: "<mark>sum</mark> of sums"},
+		{name: "no match", text: "Two Sum", term: "tree", want: ""},
+		{name: "empty term", text: "Two Sum", term: "", want: ""},
+		{name: "term longer than text", text: "Go", term: "Golang", want: ""},
+		{name: "escapes around and inside", text: `<b>"A&B"</b>`, term: "a&b", want: `&lt;b&gt;&#34;<mark>A&amp;B</mark>&#34;&lt;/b&gt;`},
+		{name: "multi-byte runes", text: "Größe über alles", term: "ÜBER", want: "Größe <mark>über</mark> alles"},
+		{name: "context elides both sides", text: "aaaaaaaaaa needle bbbbbbbbbb", term: "needle", context: 3, want: "…aa <mark>needle</mark> bb…"},
+		{name: "context at start", text: "needle bbbbbbbbbb", term: "needle", context: 3, want: "<mark>needle</mark> bb…"},
+		{name: "context at end", text: "aaaaaaaaaa needle", term: "needle", context: 3, want: "…aa <mark>needle</mark>"},
+		{name: "context counts runes, not bytes", text: "ééééé x ééééé", term: "x", context: 2, want: "…é <mark>x</mark> é…"},
+		{name: "context covering everything", text: "a needle b", term: "needle", context: 60, want: "a <mark>needle</mark> b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightMatch(tt.text, tt.term, tt.context); got != tt.want {
+				t.Errorf("highlightMatch(%q, %q, %d) = %q, want %q", tt.text, tt.term, tt.context, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	prompt := &models.Prompt{
+		Title:            "Binary search",
+		Description:      "Practice " + strings.Repeat("x", 100) + " with a sorted array",
+		ProblemStatement: "Nothing relevant here",
+	}
+
+	highlight := searchHighlight(prompt, "SORTED")
+	if _, ok := highlight["title"]; ok {
+		t.Error("title highlighted without a match")
+	}
+	if _, ok := highlight["problem_statement"]; ok {
+		t.Error("problem statement highlighted without a match")
+	}
+	if got := highlight["description"]; !strings.HasPrefix(got, "…") || !strings.Contains(got, "<mark>sorted</mark>") {
+		t.Errorf("description highlight = %q, want an elided snippet around the match", got)
+	}
+
+	if searchHighlight(prompt, "graph") != nil {
+		t.Error("want nil when nothing matches")
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/github"
+	"PromptGallery/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var repoSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// MarkdownSource lists the Markdown files of a repository directory, *github.Client in production
+type MarkdownSource interface {
+	MarkdownFiles(ctx context.Context, owner, repo, dir, ref string, limit int) ([]github.File, error)
+}
+
+type ImportService struct {
+	promptService *PromptService
+	source        MarkdownSource
+	cfg           *config.Config
+}
+
+func NewImportService(promptService *PromptService, source MarkdownSource, cfg *config.Config) *ImportService {
+	return &ImportService{
+		promptService: promptService,
+		source:        source,
+		cfg:           cfg,
+	}
+}
+
+// ImportResult reports every file of an import, one bad file doesn't stop the others
+type ImportResult struct {
+	Created []PromptResponse `json:"created"`
+	Failed  []ImportFailure  `json:"failed"`
+}
+
+type ImportFailure struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// ImportFromGitHub creates a prompt, owned by author, from each Markdown file in the directory
+// Imported prompts start unverified like any other new prompt
+func (s *ImportService) ImportFromGitHub(ctx context.Context, req *models.GitHubImportRequest, author *models.User) (*ImportResult, error) {
+	owner, repo, err := parseRepoURL(req.RepoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.source.MarkdownFiles(ctx, owner, repo, req.Path, req.Ref, s.cfg.ImportMaxFiles)
+	if err != nil {
+		if errors.Is(err, github.ErrNotFound) || strings.Contains(err.Error(), "invalid") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to fetch from github: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("invalid path: no markdown files found")
+	}
+
+	result := &ImportResult{Created: []PromptResponse{}, Failed: []ImportFailure{}}
+	for _, file := range files {
+		promptReq, err := ParsePromptMarkdown(file.Content)
+		if err == nil {
+			err = s.promptService.validateCreateRequest(promptReq)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, ImportFailure{File: file.Path, Error: err.Error()})
+			continue
+		}
+
+		created, err := s.promptService.createPrompt(promptReq, author)
+		if err != nil {
+			log.Printf("❌ Failed to import %s from %s/%s: %v", file.Path, owner, repo, err)
+			result.Failed = append(result.Failed, ImportFailure{File: file.Path, Error: "failed to create prompt"})
+			continue
+		}
+		result.Created = append(result.Created, *created)
+	}
+
+	return result, nil
+}
+
+// parseRepoURL accepts "owner/repo" or a repository URL such as https://github.com/owner/repo.git
+func parseRepoURL(repoURL string) (string, string, error) {
+	repoURL = strings.TrimSpace(repoURL)
+	if repoURL == "" {
+		return "", "", errors.New("repo_url is required")
+	}
+
+	repoPath := repoURL
+	if strings.Contains(repoURL, "://") {
+		parsed, err := url.Parse(repoURL)
+		if err != nil {
+			return "", "", errors.New("invalid repo_url")
+		}
+		repoPath = parsed.Path
+	}
+
+	segments := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if len(segments) < 2 {
+		return "", "", errors.New("invalid repo_url: expected owner/repo")
+	}
+	owner, repo := segments[0], strings.TrimSuffix(segments[1], ".git")
+	if !repoSegmentPattern.MatchString(owner) || !repoSegmentPattern.MatchString(repo) {
+		return "", "", errors.New("invalid repo_url: expected owner/repo")
+	}
+
+	return owner, repo, nil
+}
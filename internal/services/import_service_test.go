@@ -0,0 +1,124 @@
+package services
+
+import (
+	"PromptGallery/internal/github"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/testdb"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeSource serves fixed files and remembers what it was asked for
+type fakeSource struct {
+	files []github.File
+	err   error
+
+	owner, repo, dir, ref string
+	limit                 int
+}
+
+func (s *fakeSource) MarkdownFiles(ctx context.Context, owner, repo, dir, ref string, limit int) ([]github.File, error) {
+	s.owner, s.repo, s.dir, s.ref, s.limit = owner, repo, dir, ref, limit
+	return s.files, s.err
+}
+
+func TestImportFromGitHubRejectsBeforeCreating(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoURL string
+		source  *fakeSource
+		wantErr string
+	}{
+		{name: "missing repo", repoURL: "", source: &fakeSource{}, wantErr: "repo_url is required"},
+		{name: "not owner/repo", repoURL: "https://github.com/octocat", source: &fakeSource{}, wantErr: "invalid repo_url"},
+		{name: "unknown repository", repoURL: "octocat/prompts", source: &fakeSource{err: github.ErrNotFound}, wantErr: github.ErrNotFound.Error()},
+		{name: "github down", repoURL: "octocat/prompts", source: &fakeSource{err: errors.New("502 bad gateway")}, wantErr: "failed to fetch from github"},
+		{name: "no markdown", repoURL: "octocat/prompts", source: &fakeSource{}, wantErr: "no markdown files found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewImportService(nil, tt.source, testConfig())
+
+			_, err := service.ImportFromGitHub(context.Background(), &models.GitHubImportRequest{RepoURL: tt.repoURL}, &models.User{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportFromGitHub(t *testing.T) {
+	s := newTestServices(t, testConfig())
+	s.cfg.ImportMaxFiles = 20
+	author := testdb.SeedUser(t, s.db, "importer", nil)
+
+	source := &fakeSource{files: []github.File{
+		{Path: "prompts/two-sum.md", Content: `---
+title: Two Sum
+language: go
+difficulty: Beginner
+category: arrays
+tags: [arrays, hashing]
+---
+Given an array of integers, return the indices of the two numbers that add up to target.
+
+Each input has exactly one solution.
+`},
+		{Path: "prompts/no-frontmatter.md", Content: "# Just a heading\n"},
+		{Path: "prompts/bad-difficulty.md", Content: "---\ntitle: Graphs\nlanguage: go\ndifficulty: legendary\ncategory: graphs\n---\nWalk the graph.\n"},
+	}}
+	service := NewImportService(s.prompts, source, s.cfg)
+
+	result, err := service.ImportFromGitHub(context.Background(), &models.GitHubImportRequest{
+		RepoURL: "https://github.com/octocat/prompts.git",
+		Path:    "prompts",
+		Ref:     "main",
+	}, author)
+	if err != nil {
+		t.Fatalf("ImportFromGitHub: %v", err)
+	}
+
+	if source.owner != "octocat" || source.repo != "prompts" || source.dir != "prompts" || source.ref != "main" || source.limit != 20 {
+		t.Errorf("source asked for %s/%s %q at %q limit %d", source.owner, source.repo, source.dir, source.ref, source.limit)
+	}
+
+	if len(result.Created) != 1 {
+		t.Fatalf("created %d prompts, want 1 (failed: %+v)", len(result.Created), result.Failed)
+	}
+	created := result.Created[0]
+	if created.Title != "Two Sum" || created.Language != "go" || created.Difficulty != models.DifficultyBeginner ||
+		created.Category != "arrays" || created.Tags != `["arrays","hashing"]` {
+		t.Errorf("frontmatter not applied: %+v", created)
+	}
+	if created.Description != "Given an array of integers, return the indices of the two numbers that add up to target." {
+		t.Errorf("description = %q, want the body's first paragraph", created.Description)
+	}
+	if !strings.HasSuffix(created.ProblemStatement, "Each input has exactly one solution.") {
+		t.Errorf("problem statement = %q, want the whole body", created.ProblemStatement)
+	}
+	if created.IsVerified {
+		t.Error("imported prompt is verified, want a draft")
+	}
+
+	var stored models.Prompt
+	if err := s.db.First(&stored, created.ID).Error; err != nil {
+		t.Fatalf("load imported prompt: %v", err)
+	}
+	if stored.AuthorID == nil || *stored.AuthorID != author.ID {
+		t.Errorf("author_id = %v, want %d", stored.AuthorID, author.ID)
+	}
+
+	failed := map[string]string{}
+	for _, f := range result.Failed {
+		failed[f.File] = f.Error
+	}
+	if !strings.Contains(failed["prompts/no-frontmatter.md"], "missing frontmatter") {
+		t.Errorf("no-frontmatter.md failure = %q", failed["prompts/no-frontmatter.md"])
+	}
+	if failed["prompts/bad-difficulty.md"] == "" {
+		t.Error("bad-difficulty.md imported, want a failure")
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Language tag such as "fr", "pt-br" or "zh-hant-tw", after normalization
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// expandLocales turns preferred locales into lookup candidates, most specific first
+// ["fr-CA", "en"] -> ["fr-ca", "fr", "en"]
+func expandLocales(locales []string) []string {
+	var candidates []string
+
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		if !localePattern.MatchString(locale) {
+			continue
+		}
+
+		for {
+			if !slices.Contains(candidates, locale) {
+				candidates = append(candidates, locale)
+			}
+			i := strings.LastIndex(locale, "-")
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+
+	return candidates
+}
+
+// applyBestTranslation overlays the translation matching the earliest candidate onto response
+func applyBestTranslation(response *PromptResponse, translations []models.PromptTranslation, candidates []string) {
+	for _, candidate := range candidates {
+		for _, translation := range translations {
+			if translation.Locale != candidate {
+				continue
+			}
+
+			response.Title = translation.Title
+			response.Description = translation.Description
+			response.ProblemStatement = translation.ProblemStatement
+			response.Locale = translation.Locale
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestExpandLocales(t *testing.T) {
+	tests := []struct {
+		name    string
+		locales []string
+		want    []string
+	}{
+		{name: "region falls back to language", locales: []string{"fr-CA", "en"}, want: []string{"fr-ca", "fr", "en"}},
+		{name: "underscores and case", locales: []string{" PT_br "}, want: []string{"pt-br", "pt"}},
+		{name: "script and region", locales: []string{"zh-Hant-TW"}, want: []string{"zh-hant-tw", "zh-hant", "zh"}},
+		{name: "duplicates kept once", locales: []string{"fr-ca", "fr", "fr-be"}, want: []string{"fr-ca", "fr", "fr-be"}},
+		{name: "invalid tags skipped", locales: []string{"*", "english", "de"}, want: []string{"de"}},
+		{name: "none", locales: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandLocales(tt.locales); !slices.Equal(got, tt.want) {
+				t.Errorf("expandLocales(%q) = %q, want %q", tt.locales, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyBestTranslation(t *testing.T) {
+	translations := []models.PromptTranslation{
+		{Locale: "fr", Title: "Somme de deux"},
+		{Locale: "es", Title: "Suma de dos"},
+	}
+
+	tests := []struct {
+		name       string
+		candidates []string
+		wantTitle  string
+		wantLocale string
+	}{
+		{name: "first candidate wins", candidates: []string{"es", "fr"}, wantTitle: "Suma de dos", wantLocale: "es"},
+		{name: "falls back past missing locales", candidates: []string{"fr-ca", "fr"}, wantTitle: "Somme de deux", wantLocale: "fr"},
+		{name: "no match keeps the original", candidates: []string{"de"}, wantTitle: "Two Sum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := PromptResponse{Title: "Two Sum"}
+			applyBestTranslation(&response, translations, tt.candidates)
+			if response.Title != tt.wantTitle || response.Locale != tt.wantLocale {
+				t.Errorf("got title %q locale %q, want %q %q", response.Title, response.Locale, tt.wantTitle, tt.wantLocale)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RenderPromptMarkdown renders a prompt as a standalone Markdown document for sharing or printing
+// Title as H1, a metadata list, then the description and problem statement sections
+func RenderPromptMarkdown(prompt *PromptResponse) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", singleLine(prompt.Title))
+
+	fmt.Fprintf(&b, "- **Language:** %s\n", prompt.Language)
+	fmt.Fprintf(&b, "- **Difficulty:** %s\n", prompt.Difficulty)
+	fmt.Fprintf(&b, "- **Category:** %s\n", prompt.Category)
+	if tags := markdownTags(prompt.Tags); tags != "" {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", tags)
+	}
+	if prompt.AuthorName != "" {
+		fmt.Fprintf(&b, "- **Author:** %s\n", prompt.AuthorName)
+	}
+	if prompt.EstimatedTime > 0 {
+		fmt.Fprintf(&b, "- **Estimated time:** %d min\n", prompt.EstimatedTime)
+	}
+	if prompt.IsVerified {
+		b.WriteString("- **Verified:** yes\n")
+	}
+	b.WriteString("\n")
+
+	if description := strings.TrimSpace(prompt.Description); description != "" {
+		fmt.Fprintf(&b, "%s\n\n", description)
+	}
+
+	fmt.Fprintf(&b, "## Problem Statement\n\n%s\n", strings.TrimSpace(prompt.ProblemStatement))
+
+	if examples := strings.TrimSpace(prompt.Examples); examples != "" {
+		fmt.Fprintf(&b, "\n## Examples\n\n%s\n", examples)
+	}
+	if hints := strings.TrimSpace(prompt.Hints); hints != "" {
+		fmt.Fprintf(&b, "\n## Hints\n\n%s\n", hints)
+	}
+
+	return b.String()
+}
+
+// markdownTags renders the JSON tag array as "a, b, c", falling back to the raw value
+func markdownTags(raw string) string {
+	var tags []string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		return strings.TrimSpace(raw)
+	}
+	return strings.Join(tags, ", ")
+}
+
+// singleLine keeps a heading on one line so a newline in the title can't break the document
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
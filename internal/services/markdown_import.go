@@ -0,0 +1,102 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ParsePromptMarkdown reads a prompt written as Markdown with a YAML-style frontmatter block:
+//
+//	---
+//	title: Two Sum
+//	language: go
+//	difficulty: beginner
+//	category: arrays
+//	tags: [arrays, hashing]
+//	---
+//	Given an array of integers...
+//
+// The body becomes the problem statement. Without a description key the first paragraph
+// of the body is used. Only flat "key: value" lines are understood, unknown keys are ignored
+func ParsePromptMarkdown(content string) (*models.PromptCreateRequest, error) {
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff")
+
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return nil, errors.New("missing frontmatter")
+	}
+	frontmatter, body, ok := strings.Cut(rest, "\n---")
+	if !ok {
+		return nil, errors.New("unterminated frontmatter")
+	}
+	// Drop the rest of the closing "---" line
+	if _, after, found := strings.Cut(body, "\n"); found {
+		body = after
+	} else {
+		body = ""
+	}
+
+	req := &models.PromptCreateRequest{ProblemStatement: strings.TrimSpace(body)}
+
+	for _, line := range strings.Split(frontmatter, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		value = unquote(strings.TrimSpace(value))
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			req.Title = value
+		case "description":
+			req.Description = value
+		case "language":
+			req.Language = value
+		case "difficulty":
+			req.Difficulty = models.DifficultyLevel(strings.ToLower(value))
+		case "category":
+			req.Category = value
+		case "tags":
+			req.Tags = frontmatterList(value)
+		case "estimated_time":
+			req.EstimatedTime, _ = strconv.Atoi(value)
+		case "author", "author_name":
+			req.AuthorName = value
+		}
+	}
+
+	if req.Description == "" {
+		paragraph, _, _ := strings.Cut(req.ProblemStatement, "\n\n")
+		req.Description = strings.TrimSpace(paragraph)
+	}
+
+	return req, nil
+}
+
+// frontmatterList turns "[a, b]" or "a, b" into a JSON array string
+func frontmatterList(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+
+	encoded, _ := json.Marshal(items)
+	return string(encoded)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPromptMarkdown(t *testing.T) {
+	prompt := &PromptResponse{
+		Title:            "Two\nSum",
+		Language:         "go",
+		Difficulty:       "beginner",
+		Category:         "arrays",
+		Tags:             `["hash-map","arrays"]`,
+		AuthorName:       "Ann",
+		EstimatedTime:    15,
+		IsVerified:       true,
+		Description:      "  Find two numbers.  ",
+		ProblemStatement: "Return the indices.\n",
+		Examples:         "[2,7,11], 9 -> [0,1]",
+		Hints:            " ",
+	}
+
+	want := `# Two Sum
+
+- **Language:** go
+- **Difficulty:** beginner
+- **Category:** arrays
+- **Tags:** hash-map, arrays
+- **Author:** Ann
+- **Estimated time:** 15 min
+- **Verified:** yes
+
+Find two numbers.
+
+## Problem Statement
+
+Return the indices.
+
+## Examples
+
+[2,7,11], 9 -> [0,1]
+`
+
+	if got := RenderPromptMarkdown(prompt); got != want {
+		t.Errorf("RenderPromptMarkdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderPromptMarkdownOmitsEmptyParts(t *testing.T) {
+	got := RenderPromptMarkdown(&PromptResponse{Title: "Fizz", Language: "py", Difficulty: "beginner", Category: "basics", ProblemStatement: "Print it."})
+
+	for _, absent := range []string{"Tags", "Author", "Estimated time", "Verified", "## Examples", "## Hints"} {
+		if strings.Contains(got, absent) {
+			t.Errorf("output mentions %q:\n%s", absent, got)
+		}
+	}
+	if !strings.HasSuffix(got, "## Problem Statement\n\nPrint it.\n") {
+		t.Errorf("unexpected ending:\n%s", got)
+	}
+}
+
+func TestMarkdownTagsFallsBackToRaw(t *testing.T) {
+	if got := markdownTags(" legacy,tags "); got != "legacy,tags" {
+		t.Errorf("markdownTags = %q", got)
+	}
+}
@@ -1,19 +1,36 @@
 package services
 
 import (
+	"PromptGallery/internal/config"
 	"PromptGallery/internal/models"
 	"PromptGallery/internal/repositories"
+	"PromptGallery/internal/worker"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type PromptService struct {
 	promptRepo *repositories.PromptRepository
+	userRepo   *repositories.UserRepository // expands user references like verified_by
+	cfg        *config.Config
+	tasks      *worker.Pool // async side effects like view counting
+	anonLikes  *recentLikes // IP+prompt pairs that liked anonymously within the window
 }
 
-func NewPromptService(promptRepo *repositories.PromptRepository) *PromptService {
+func NewPromptService(promptRepo *repositories.PromptRepository, userRepo *repositories.UserRepository, cfg *config.Config, tasks *worker.Pool) *PromptService {
 	return &PromptService{
 		promptRepo: promptRepo,
+		userRepo:   userRepo,
+		cfg:        cfg,
+		tasks:      tasks,
+		anonLikes:  newRecentLikes(),
 	}
 }
 
@@ -26,13 +43,49 @@ type PromptResponse struct {
 	Difficulty       models.DifficultyLevel `json:"difficulty"`
 	Category         string                 `json:"category"`
 	ProblemStatement string                 `json:"problem_statement"`
+	Examples         string                 `json:"examples,omitempty"`
+	Hints            string                 `json:"hints,omitempty"`
+	EstimatedTime    int                    `json:"estimated_time,omitempty"` // Minutes
 	IsVerified       bool                   `json:"is_verified"`
 	ViewCount        int                    `json:"view_count"`
 	LikeCount        int                    `json:"like_count"`
 	Tags             string                 `json:"tags"`
 	AuthorName       string                 `json:"author_name,omitempty"`
+	ExternalID       string                 `json:"external_id,omitempty"`
+	QualityScore     float64                `json:"quality_score"`
+	Verifier         *PromptVerifier        `json:"verifier,omitempty"`   // Only with ?include=verifier on verified prompts
+	Warnings         []string               `json:"warnings,omitempty"`   // Non-blocking data-quality nudges on create
+	IsDeleted        bool                   `json:"is_deleted,omitempty"` // Only soft-deleted prompts fetched by moderators
+	DeletedAt        string                 `json:"deleted_at,omitempty"`
+	Locale           string                 `json:"locale,omitempty"`    // Set when translated content was served
+	Highlight        map[string]string      `json:"highlight,omitempty"` // List with ?search= only, matched fields with the term in <mark>
 	CreatedAt        string                 `json:"created_at"`
 	UpdatedAt        string                 `json:"updated_at"`
+
+	ETag string `json:"-"` // Sent as the ETag header on single-prompt responses
+}
+
+// PromptVerifier is the public identity of the moderator who verified a prompt
+type PromptVerifier struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// PromptDetailOptions tunes what GetPromptByID returns
+type PromptDetailOptions struct {
+	Locales         []string // Preferred locales, best first
+	IncludeDeleted  bool     // Also find soft-deleted prompts, callers must restrict it to moderators
+	IncludeVerifier bool     // Expand verified_by into a verifier object
+	VerifiedOnly    bool     // Report unverified prompts as not found
+}
+
+type PromptTranslationResponse struct {
+	Locale           string `json:"locale"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	ProblemStatement string `json:"problem_statement"`
+	UpdatedAt        string `json:"updated_at"`
 }
 
 type PaginationPromptResponse struct {
@@ -41,22 +94,44 @@ type PaginationPromptResponse struct {
 	Page       int              `json:"page"`
 	Limit      int              `json:"limit"`
 	TotalPages int              `json:"total_pages"`
+	Notice     string           `json:"notice,omitempty"` // e.g. why the search term was ignored
 }
 
-func (s *PromptService) GetAllPrompts(filter models.PromptFilter, page, limit int) (*PaginationPromptResponse, error) {
-	if page < 1 {
-		page = 1
-	}
+type LikeToggleResponse struct {
+	Liked     bool `json:"liked"`
+	LikeCount int  `json:"like_count"`
+}
 
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
+type PromptCountResponse struct {
+	Total  int64  `json:"total"`
+	Notice string `json:"notice,omitempty"`
+}
+
+func (s *PromptService) GetAllPrompts(filter models.PromptFilter, pagination models.PaginationParams) (*PaginationPromptResponse, error) {
+	pagination.Normalize()
 
 	if filter.Difficulty != "" && !filter.Difficulty.Valid() {
 		return nil, errors.New("invalid difficulty")
 	}
 
-	prompts, total, err := s.promptRepo.FindAll(filter, page, limit)
+	if filter.Sort == "" {
+		filter.Sort = models.PromptSort(s.cfg.DefaultPromptSort)
+	}
+	if !filter.Sort.Valid() {
+		return nil, errors.New("invalid sort option")
+	}
+
+	notice := s.guardSearch(&filter)
+
+	var prompts []models.Prompt
+	var total int64
+	var err error
+
+	if filter.Ordered && len(filter.IDs) > 0 {
+		prompts, total, err = s.findInRequestedOrder(filter, pagination)
+	} else {
+		prompts, total, err = s.promptRepo.FindAll(filter, pagination)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -64,58 +139,638 @@ func (s *PromptService) GetAllPrompts(filter models.PromptFilter, page, limit in
 	promptResponses := make([]PromptResponse, len(prompts))
 	for i, prompt := range prompts {
 		promptResponses[i] = s.transformToResponse(&prompt)
+		if filter.Search != "" {
+			promptResponses[i].Highlight = searchHighlight(&prompt, filter.Search)
+		}
 	}
 
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
-
 	return &PaginationPromptResponse{
 		Data:       promptResponses,
 		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalPages: pagination.TotalPages(total),
+		Notice:     notice,
 	}, nil
 
 }
 
-func (s *PromptService) GetPromptByID(id uint) (*PromptResponse, error) {
+// GetDailyPrompt picks the daily challenge for day (UTC), optionally of one difficulty
+// The pick is seeded by the date, so every caller gets the same verified prompt all day
+func (s *PromptService) GetDailyPrompt(difficulty models.DifficultyLevel, day time.Time) (*PromptResponse, error) {
+	if difficulty != "" && !difficulty.Valid() {
+		return nil, errors.New("invalid difficulty")
+	}
+
+	filter := models.PromptFilter{
+		Difficulty:   difficulty,
+		Seed:         fmt.Sprintf("daily:%s:%s", day.UTC().Format(time.DateOnly), difficulty),
+		VerifiedOnly: true, // one pool for everyone, whatever they may see elsewhere
+	}
+
+	prompts, _, err := s.promptRepo.FindAll(filter, models.PaginationParams{Page: 1, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find daily prompt: %w", err)
+	}
+	if len(prompts) == 0 {
+		return nil, errors.New("no prompts found for the daily challenge")
+	}
+
+	response := s.transformToResponse(&prompts[0])
+	return &response, nil
+}
+
+// GetStudyMix returns up to limit prompts interleaving the difficulties, easiest first in each round
+// Each difficulty is fetched as its own bucket with the usual filters and sort; when a bucket
+// runs out the remaining ones keep filling the page
+func (s *PromptService) GetStudyMix(filter models.PromptFilter, limit int) ([]PromptResponse, error) {
+	if limit < 1 || limit > models.MaxLimit {
+		limit = models.DefaultLimit
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = models.PromptSort(s.cfg.DefaultPromptSort)
+	}
+	if !filter.Sort.Valid() {
+		return nil, errors.New("invalid sort option")
+	}
+	s.guardSearch(&filter)
+	filter.Ordered = false
+
+	buckets := make([][]models.Prompt, 0, len(models.DifficultyLevels))
+	for _, level := range models.DifficultyLevels {
+		bucketFilter := filter
+		bucketFilter.Difficulty = level
+
+		prompts, _, err := s.promptRepo.FindAll(bucketFilter, models.PaginationParams{Page: 1, Limit: limit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s prompts: %w", level, err)
+		}
+		buckets = append(buckets, prompts)
+	}
+
+	prompts := interleave(buckets, limit)
+	mix := make([]PromptResponse, 0, len(prompts))
+	for i := range prompts {
+		mix = append(mix, s.transformToResponse(&prompts[i]))
+	}
+
+	return mix, nil
+}
+
+// interleave takes one prompt from each bucket per round, in bucket order, until limit
+// prompts are picked or every bucket is empty
+func interleave(buckets [][]models.Prompt, limit int) []models.Prompt {
+	mix := make([]models.Prompt, 0, limit)
+	for round := 0; len(mix) < limit; round++ {
+		added := false
+		for _, bucket := range buckets {
+			if round < len(bucket) && len(mix) < limit {
+				mix = append(mix, bucket[round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return mix
+}
+
+// findInRequestedOrder returns the page of filter.IDs in the order the client listed them
+// All matches are fetched (ids are capped at 100) so pagination follows the curated order, not DB order
+func (s *PromptService) findInRequestedOrder(filter models.PromptFilter, pagination models.PaginationParams) ([]models.Prompt, int64, error) {
+	prompts, total, err := s.promptRepo.FindAll(filter, models.PaginationParams{Page: 1, Limit: len(filter.IDs)})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	position := make(map[uint]int, len(filter.IDs))
+	for i, id := range filter.IDs {
+		position[id] = i
+	}
+	slices.SortFunc(prompts, func(a, b models.Prompt) int {
+		return position[a.ID] - position[b.ID]
+	})
+
+	start := min(pagination.Offset(), len(prompts))
+	end := min(start+pagination.Limit, len(prompts))
+	return prompts[start:end], total, nil
+}
+
+func (s *PromptService) CountPrompts(filter models.PromptFilter) (*PromptCountResponse, error) {
+	if filter.Difficulty != "" && !filter.Difficulty.Valid() {
+		return nil, errors.New("invalid difficulty")
+	}
+
+	notice := s.guardSearch(&filter)
+
+	total, err := s.promptRepo.Count(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count prompts: %w", err)
+	}
+	return &PromptCountResponse{Total: total, Notice: notice}, nil
+}
+
+// guardSearch drops search terms below MinSearchLength, a one-letter '%a%' LIKE scans the whole table
+// Returns a notice for the client when the term was ignored
+func (s *PromptService) guardSearch(filter *models.PromptFilter) string {
+	filter.Search = strings.TrimSpace(filter.Search)
+	if filter.Search == "" || utf8.RuneCountInString(filter.Search) >= s.cfg.MinSearchLength {
+		return ""
+	}
+
+	filter.Search = ""
+	return fmt.Sprintf("search ignored: term must be at least %d characters", s.cfg.MinSearchLength)
+}
+
+// GetPromptByID returns the prompt, translated to the first of the preferred locales
+// that has a translation. Falls back to the default content when none match
+func (s *PromptService) GetPromptByID(id uint, opts PromptDetailOptions) (*PromptResponse, error) {
 	if id == 0 {
 		return nil, errors.New("invalid prompt id")
 	}
 
-	prompt, err := s.promptRepo.FindByID(id)
+	var prompt *models.Prompt
+	var err error
+	if opts.IncludeDeleted {
+		prompt, err = s.promptRepo.FindByIDUnscoped(id)
+	} else {
+		prompt, err = s.promptRepo.FindByID(id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to find prompt: %w", err)
 	}
-
-	go func() {
-		_ = s.promptRepo.IncrementViewCount(id)
-	}()
+	if opts.VerifiedOnly && !prompt.IsVerified {
+		return nil, errors.New("prompt not found")
+	}
 
 	response := s.transformToResponse(prompt)
+
+	if prompt.DeletedAt.Valid {
+		response.IsDeleted = true
+		response.DeletedAt = formatTimestamp(prompt.DeletedAt.Time)
+	} else {
+		s.tasks.Submit(func() {
+			if err := s.promptRepo.IncrementViewCount(id); err != nil {
+				log.Printf("❌ Failed to count view for prompt %d: %v", id, err)
+			}
+		})
+	}
+
+	if candidates := expandLocales(opts.Locales); len(candidates) > 0 {
+		translations, err := s.promptRepo.FindTranslations(id, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find translations: %w", err)
+		}
+		applyBestTranslation(&response, translations, candidates)
+	}
+
+	if opts.IncludeVerifier {
+		if err := s.attachVerifiers([]*PromptResponse{&response}, []*models.Prompt{prompt}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &response, nil
+}
+
+// attachVerifiers fills Verifier on responses[i] from prompts[i].VerifiedBy
+// All verifiers are loaded in one query, unverified prompts keep a nil verifier
+func (s *PromptService) attachVerifiers(responses []*PromptResponse, prompts []*models.Prompt) error {
+	var ids []uint
+	for _, prompt := range prompts {
+		if prompt.IsVerified && prompt.VerifiedBy != nil && !slices.Contains(ids, *prompt.VerifiedBy) {
+			ids = append(ids, *prompt.VerifiedBy)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.FindByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("failed to load verifiers: %w", err)
+	}
+
+	verifiers := make(map[uint]*PromptVerifier, len(users))
+	for _, user := range users {
+		verifiers[user.ID] = &PromptVerifier{ID: user.ID, Username: user.Username, Name: user.Name}
+	}
+
+	for i, prompt := range prompts {
+		if prompt.IsVerified && prompt.VerifiedBy != nil {
+			responses[i].Verifier = verifiers[*prompt.VerifiedBy]
+		}
+	}
+	return nil
+}
+
+func (s *PromptService) GetTranslations(promptID uint, verifiedOnly bool) ([]PromptTranslationResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+
+	if verifiedOnly {
+		if err := s.checkVerified(promptID); err != nil {
+			return nil, err
+		}
+	} else {
+		exists, err := s.promptRepo.Exists(promptID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if prompt exists: %w", err)
+		}
+		if !exists {
+			return nil, errors.New("prompt not found")
+		}
+	}
+
+	translations, err := s.promptRepo.FindTranslations(promptID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find translations: %w", err)
+	}
+
+	responses := make([]PromptTranslationResponse, len(translations))
+	for i, translation := range translations {
+		responses[i] = transformTranslation(&translation)
+	}
+	return responses, nil
+}
+
+// checkVerified reports an unverified prompt as not found, for callers below MIN_ROLE_TO_VIEW_UNVERIFIED
+// Same answer GetPromptByID gives them, so hidden prompts can't be probed through other endpoints
+func (s *PromptService) checkVerified(promptID uint) error {
+	prompt, err := s.promptRepo.FindByID(promptID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		return fmt.Errorf("failed to find prompt: %w", err)
+	}
+	if !prompt.IsVerified {
+		return errors.New("prompt not found")
+	}
+	return nil
+}
+
+// UpsertTranslation adds the prompt's translation for locale, replacing any existing one
+// Only the prompt's author and moderators may write translations
+func (s *PromptService) UpsertTranslation(promptID uint, locale string, req *models.PromptTranslationRequest, editor *models.User) (*PromptTranslationResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+
+	locale = normalizeLocale(locale)
+	if !localePattern.MatchString(locale) {
+		return nil, errors.New("invalid locale")
+	}
+
+	if req.Title == "" {
+		return nil, errors.New("title is required")
+	}
+	if len(req.Title) > 200 {
+		return nil, errors.New("invalid title: must be less than 200 characters")
+	}
+	if req.Description == "" {
+		return nil, errors.New("description is required")
+	}
+	if req.ProblemStatement == "" {
+		return nil, errors.New("problem statement is required")
+	}
+	if err := s.checkContentLength(req.Description, req.ProblemStatement); err != nil {
+		return nil, err
+	}
+
+	prompt, err := s.promptRepo.FindByID(promptID)
+	if err != nil {
+		return nil, err
+	}
+	isAuthor := prompt.AuthorID != nil && *prompt.AuthorID == editor.ID
+	if !isAuthor && !editor.Role.CanVerifyPrompts() {
+		return nil, errors.New("not allowed: only the author or a moderator can translate this prompt")
+	}
+
+	translation, err := s.promptRepo.UpsertTranslation(&models.PromptTranslation{
+		PromptID:         promptID,
+		Locale:           locale,
+		Title:            req.Title,
+		Description:      req.Description,
+		ProblemStatement: req.ProblemStatement,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+
+	response := transformTranslation(translation)
 	return &response, nil
 }
 
 func (s *PromptService) CreatePrompt(createReq *models.PromptCreateRequest) (*PromptResponse, error) {
+	return s.createPrompt(createReq, nil)
+}
+
+// PromptValidationResult reports a create body that passed validation, with any review warnings
+type PromptValidationResult struct {
+	Valid    bool     `json:"valid"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ValidatePrompt runs the create validation without storing anything
+// A failing body returns the same error CreatePrompt would
+func (s *PromptService) ValidatePrompt(createReq *models.PromptCreateRequest) (*PromptValidationResult, error) {
+	if err := s.validateCreateRequest(createReq); err != nil {
+		return nil, err
+	}
+
+	result := &PromptValidationResult{Valid: true}
+
+	prompt := createReq.ToPrompt()
+	if prompt.Difficulty == "" {
+		prompt.Difficulty = models.DifficultyBeginner
+	}
+	if warning := s.difficultyMismatchWarning(prompt); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+	return result, nil
+}
+
+// createPrompt validates and stores a prompt, owned by author when one is given
+func (s *PromptService) createPrompt(createReq *models.PromptCreateRequest, author *models.User) (*PromptResponse, error) {
 	if err := s.validateCreateRequest(createReq); err != nil {
 		return nil, err
 	}
 
 	prompt := createReq.ToPrompt()
 
+	if author != nil {
+		prompt.AuthorID = &author.ID
+		if strings.TrimSpace(prompt.AuthorName) == "" {
+			prompt.AuthorName = author.Name
+		}
+	}
+
 	if prompt.Difficulty == "" {
 		prompt.Difficulty = models.DifficultyBeginner
 	}
 
+	s.applyDefaultAuthor(prompt)
+
 	createdPrompt, err := s.promptRepo.Create(prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create prompt: %w", err)
 	}
 
 	response := s.transformToResponse(createdPrompt)
+	if warning := s.difficultyMismatchWarning(createdPrompt); warning != "" {
+		response.Warnings = append(response.Warnings, warning)
+	}
 	return &response, nil
 }
 
+// Distinct advanced keywords a beginner prompt may mention before it's flagged
+const mismatchKeywordThreshold = 2
+
+// difficultyMismatchWarning nudges a review when a beginner prompt reads like an advanced one
+// Returns "" when the prompt isn't beginner or mentions fewer than mismatchKeywordThreshold keywords
+func (s *PromptService) difficultyMismatchWarning(prompt *models.Prompt) string {
+	if prompt.Difficulty != models.DifficultyBeginner {
+		return ""
+	}
+
+	statement := strings.ToLower(prompt.ProblemStatement)
+	var found []string
+	for _, keyword := range s.cfg.AdvancedKeywords {
+		if strings.Contains(statement, keyword) {
+			found = append(found, keyword)
+		}
+	}
+	if len(found) < mismatchKeywordThreshold {
+		return ""
+	}
+
+	return fmt.Sprintf("marked beginner but the problem statement mentions %s, consider reviewing the difficulty", strings.Join(found, ", "))
+}
+
+// UpdatePrompt applies editor's partial edit to a prompt, editor must be its author or a moderator
+// The edited prompt goes through the create validation again, so an edit can't store what create would reject
+// Changing the verified content puts the prompt back to unverified, it has to be reviewed again
+func (s *PromptService) UpdatePrompt(id uint, req *models.PromptUpdateRequest, editor *models.User) (*PromptResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+
+	prompt, err := s.promptRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	isAuthor := prompt.AuthorID != nil && *prompt.AuthorID == editor.ID
+	if !isAuthor && !editor.Role.CanVerifyPrompts() {
+		return nil, errors.New("not allowed: only the author or a moderator can edit this prompt")
+	}
+
+	unverify := prompt.IsVerified && req.ChangesContent(prompt)
+	req.ApplyTo(prompt)
+
+	edited := &models.PromptCreateRequest{
+		Title:            prompt.Title,
+		Description:      prompt.Description,
+		Language:         prompt.Language,
+		Difficulty:       prompt.Difficulty,
+		Category:         prompt.Category,
+		ProblemStatement: prompt.ProblemStatement,
+		Examples:         prompt.Examples,
+		Hints:            prompt.Hints,
+		EstimatedTime:    prompt.EstimatedTime,
+		Tags:             prompt.Tags,
+		AuthorName:       prompt.AuthorName,
+		AuthorEmail:      prompt.AuthorEmail,
+	}
+	if err := s.validateCreateRequest(edited); err != nil {
+		return nil, err
+	}
+	prompt.Tags = edited.Tags
+
+	if unverify {
+		_, err = s.promptRepo.UpdateUnverified(prompt, models.UnverifyAudit(id, editor.ID, "content edited"))
+	} else {
+		_, err = s.promptRepo.Update(prompt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update prompt: %w", err)
+	}
+
+	// Re-read so updated_at (and the ETag) carry the precision the database stored
+	updatedPrompt, err := s.promptRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.transformToResponse(updatedPrompt)
+	if warning := s.difficultyMismatchWarning(updatedPrompt); warning != "" {
+		response.Warnings = append(response.Warnings, warning)
+	}
+	return &response, nil
+}
+
+// UpsertPromptByExternalID creates or updates the prompt an external system knows as externalID
+// Returns created=true when a new prompt was inserted, so repeated syncs never duplicate
+func (s *PromptService) UpsertPromptByExternalID(externalID string, req *models.PromptCreateRequest) (*PromptResponse, bool, error) {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return nil, false, errors.New("external id is required")
+	}
+	if len(externalID) > 100 {
+		return nil, false, errors.New("invalid external id: must be at most 100 characters")
+	}
+
+	if err := s.validateCreateRequest(req); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.promptRepo.FindByExternalID(externalID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, false, fmt.Errorf("failed to look up prompt: %w", err)
+	}
+
+	if existing == nil {
+		prompt := req.ToPrompt()
+		prompt.ExternalID = &externalID
+		if prompt.Difficulty == "" {
+			prompt.Difficulty = models.DifficultyBeginner
+		}
+		s.applyDefaultAuthor(prompt)
+
+		createdPrompt, err := s.promptRepo.Create(prompt)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create prompt: %w", err)
+		}
+
+		response := s.transformToResponse(createdPrompt)
+		return &response, true, nil
+	}
+
+	req.ApplyTo(existing)
+	if existing.Difficulty == "" {
+		existing.Difficulty = models.DifficultyBeginner
+	}
+
+	// A soft-deleted prompt still owns the external id, re-syncing it brings it back
+	if _, err := s.promptRepo.Restore(existing); err != nil {
+		return nil, false, fmt.Errorf("failed to update prompt: %w", err)
+	}
+
+	updatedPrompt, err := s.promptRepo.FindByID(existing.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	response := s.transformToResponse(updatedPrompt)
+	return &response, false, nil
+}
+
+// applyDefaultAuthor gives anonymous submissions a placeholder author so the UI never shows a blank name
+func (s *PromptService) applyDefaultAuthor(prompt *models.Prompt) {
+	if strings.TrimSpace(prompt.AuthorName) == "" && strings.TrimSpace(prompt.AuthorEmail) == "" && prompt.AuthorID == nil {
+		prompt.AuthorName = s.cfg.DefaultAuthorName
+	}
+}
+
+// ToggleLike likes the prompt for userID, or removes the like if it's already there
+// verifiedOnly treats unverified prompts as not found, like the detail endpoint
+func (s *PromptService) ToggleLike(promptID, userID uint, verifiedOnly bool) (*LikeToggleResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+	if verifiedOnly {
+		if err := s.checkVerified(promptID); err != nil {
+			return nil, err
+		}
+	}
+
+	liked, likeCount, err := s.promptRepo.ToggleLike(promptID, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to toggle like: %w", err)
+	}
+
+	return &LikeToggleResponse{Liked: liked, LikeCount: likeCount}, nil
+}
+
+// VerifyPrompt marks a prompt verified by verifierID, verifying a verified prompt changes nothing
+func (s *PromptService) VerifyPrompt(promptID, verifierID uint) (*PromptResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+
+	if _, err := s.promptRepo.MarkVerified(promptID, verifierID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to verify prompt: %w", err)
+	}
+
+	return s.reloadPrompt(promptID)
+}
+
+// UnverifyPrompt clears a prompt's verification, a no-op on an unverified prompt
+// The moderator has to say why, the reason goes to the audit log with their id
+func (s *PromptService) UnverifyPrompt(promptID, actorID uint, reason string) (*PromptResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	if utf8.RuneCountInString(reason) > models.MaxAuditReasonLength {
+		return nil, fmt.Errorf("invalid reason: must be at most %d characters", models.MaxAuditReasonLength)
+	}
+
+	if _, err := s.promptRepo.ClearVerification(promptID, models.UnverifyAudit(promptID, actorID, reason)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to unverify prompt: %w", err)
+	}
+
+	return s.reloadPrompt(promptID)
+}
+
+func (s *PromptService) reloadPrompt(id uint) (*PromptResponse, error) {
+	prompt, err := s.promptRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.transformToResponse(prompt)
+	return &response, nil
+}
+
+// LikePrompt likes the prompt for userID, liking an already liked prompt changes nothing
+// verifiedOnly treats unverified prompts as not found, like the detail endpoint
+func (s *PromptService) LikePrompt(promptID, userID uint, verifiedOnly bool) (*LikeToggleResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+	if verifiedOnly {
+		if err := s.checkVerified(promptID); err != nil {
+			return nil, err
+		}
+	}
+
+	likeCount, err := s.promptRepo.AddLike(promptID, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to like prompt: %w", err)
+	}
+
+	return &LikeToggleResponse{Liked: true, LikeCount: likeCount}, nil
+}
+
 func (s *PromptService) DeletePrompt(id uint) error {
 	if id == 0 {
 		return errors.New("invalid prompt id")
@@ -137,6 +792,195 @@ func (s *PromptService) DeletePrompt(id uint) error {
 	return nil
 }
 
+// DeletePromptIfMatch deletes a prompt only while its ETag still matches the If-Match value
+// "*" matches any existing prompt, a list matches if any entry does (strong comparison)
+func (s *PromptService) DeletePromptIfMatch(id uint, ifMatch string) error {
+	if id == 0 {
+		return errors.New("invalid prompt id")
+	}
+
+	prompt, err := s.promptRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if !etagMatches(ifMatch, prompt.ETag()) {
+		return errors.New("precondition failed: prompt was modified")
+	}
+
+	if err := s.promptRepo.DeleteIfUnchanged(id, prompt.UpdatedAt); err != nil {
+		if strings.Contains(err.Error(), "precondition failed") {
+			return err
+		}
+		return fmt.Errorf("failed to delete prompt: %w", err)
+	}
+	return nil
+}
+
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// MergePrompts folds duplicate prompts into the kept one and returns it with the combined counts
+func (s *PromptService) MergePrompts(req *models.PromptMergeRequest) (*PromptResponse, error) {
+	if req.Keep == 0 {
+		return nil, errors.New("keep is required")
+	}
+
+	var mergeIDs []uint
+	for _, id := range req.Merge {
+		if id == 0 || id == req.Keep {
+			return nil, errors.New("invalid merge id: must be a different, existing prompt")
+		}
+		if !slices.Contains(mergeIDs, id) {
+			mergeIDs = append(mergeIDs, id)
+		}
+	}
+	if len(mergeIDs) == 0 {
+		return nil, errors.New("merge ids are required")
+	}
+
+	if err := s.promptRepo.MergeInto(req.Keep, mergeIDs); err != nil {
+		return nil, fmt.Errorf("failed to merge prompts: %w", err)
+	}
+
+	kept, err := s.promptRepo.FindByID(req.Keep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find prompt: %w", err)
+	}
+
+	response := s.transformToResponse(kept)
+	return &response, nil
+}
+
+// PurgeDeletedPrompts permanently removes prompts soft-deleted more than retention ago
+func (s *PromptService) PurgeDeletedPrompts(retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, errors.New("invalid retention period")
+	}
+
+	purged, err := s.promptRepo.PurgeDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted prompts: %w", err)
+	}
+	return purged, nil
+}
+
+// SnapshotCounts records every prompt's counters now and drops snapshots older than retention
+func (s *PromptService) SnapshotCounts(retention time.Duration) (int64, error) {
+	now := time.Now()
+
+	taken, err := s.promptRepo.SnapshotCounts(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot counts: %w", err)
+	}
+
+	if retention > 0 {
+		if _, err := s.promptRepo.PruneSnapshotsBefore(now.Add(-retention)); err != nil {
+			return taken, fmt.Errorf("failed to prune count snapshots: %w", err)
+		}
+	}
+	return taken, nil
+}
+
+// GetCountDeltas reports how far the prompts' view/like counts moved since the given time
+// The baseline is the latest snapshot at or before since, so the precision is the snapshot interval
+func (s *PromptService) GetCountDeltas(ids []uint, since time.Time, verifiedOnly bool) ([]models.PromptCountDelta, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids is required")
+	}
+	if since.After(time.Now()) {
+		return nil, errors.New("invalid since: must not be in the future")
+	}
+
+	deltas, err := s.promptRepo.CountDeltas(ids, since, verifiedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute count deltas: %w", err)
+	}
+	return deltas, nil
+}
+
+// GetTrendingTags returns the tags most used on prompts created within the window
+func (s *PromptService) GetTrendingTags(window time.Duration, limit int) ([]models.TagUsage, error) {
+	if window <= 0 {
+		return nil, errors.New("invalid window")
+	}
+	if limit < 1 || limit > models.MaxTrendingTagsLimit {
+		limit = models.DefaultTrendingTagsLimit
+	}
+
+	tags, err := s.promptRepo.TrendingTags(time.Now().Add(-window), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate trending tags: %w", err)
+	}
+	if tags == nil {
+		tags = []models.TagUsage{}
+	}
+	return tags, nil
+}
+
+// AnonymousLike adds a like from a caller without an account, identified only by IP
+// A repeat from the same IP for the same prompt within the window is ignored and just
+// reports the current count. Anonymous likes can't be taken back
+func (s *PromptService) AnonymousLike(promptID uint, ip string, verifiedOnly bool) (*LikeToggleResponse, error) {
+	if promptID == 0 {
+		return nil, errors.New("invalid prompt id")
+	}
+	if verifiedOnly {
+		if err := s.checkVerified(promptID); err != nil {
+			return nil, err
+		}
+	}
+
+	key := fmt.Sprintf("%s|%d", ip, promptID)
+	window := time.Duration(s.cfg.AnonymousLikeWindowSeconds) * time.Second
+
+	if !s.anonLikes.claim(key, window, time.Now()) {
+		prompt, err := s.promptRepo.FindByID(promptID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find prompt: %w", err)
+		}
+		return &LikeToggleResponse{Liked: true, LikeCount: prompt.LikeCount}, nil
+	}
+
+	likeCount, err := s.promptRepo.IncrementLikeCount(promptID)
+	if err != nil {
+		s.anonLikes.release(key)
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to add like: %w", err)
+	}
+
+	return &LikeToggleResponse{Liked: true, LikeCount: likeCount}, nil
+}
+
+// GetLanguageEngagement returns the per-language leaderboard, most viewed first by default
+func (s *PromptService) GetLanguageEngagement(sort models.LanguageEngagementSort) ([]models.LanguageEngagement, error) {
+	if sort == "" {
+		sort = models.EngagementSortViews
+	}
+	if !sort.Valid() {
+		return nil, errors.New("invalid sort option")
+	}
+
+	stats, err := s.promptRepo.LanguageEngagement(sort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate language engagement: %w", err)
+	}
+
+	for i := range stats {
+		stats[i].AvgDifficulty = math.Round(stats[i].AvgDifficulty*100) / 100
+	}
+	return stats, nil
+}
+
 func (s *PromptService) GetPopularPrompts(limit int) ([]PromptResponse, error) {
 	// Business logic - validate limit
 	if limit < 1 || limit > 50 {
@@ -158,6 +1002,7 @@ func (s *PromptService) GetPopularPrompts(limit int) ([]PromptResponse, error) {
 	return responses, nil
 }
 
+// validateCreateRequest checks a create/upsert body and normalizes its tags in place
 func (s *PromptService) validateCreateRequest(req *models.PromptCreateRequest) error {
 	if req.Title == "" {
 		return errors.New("title is required")
@@ -180,10 +1025,185 @@ func (s *PromptService) validateCreateRequest(req *models.PromptCreateRequest) e
 	if req.Difficulty != "" && !req.Difficulty.Valid() {
 		return errors.New("invalid difficulty level")
 	}
+	if req.EstimatedTime < 0 {
+		return errors.New("invalid estimated time: must not be negative")
+	}
+	if err := s.checkLanguage(req.Language); err != nil {
+		return err
+	}
+	if err := s.checkContentLength(req.Description, req.ProblemStatement); err != nil {
+		return err
+	}
+
+	tags, err := s.normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	req.Tags = tags
+
+	difficulty := req.Difficulty
+	if difficulty == "" {
+		difficulty = models.DifficultyBeginner
+	}
+	if err := s.checkCategoryDifficulty(req.Category, difficulty); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// checkContentLength caps the long text fields so a single field can't carry megabytes of text
+func (s *PromptService) checkContentLength(description, problemStatement string) error {
+	if limit := s.cfg.MaxDescriptionLength; limit > 0 && utf8.RuneCountInString(description) > limit {
+		return &ValidationError{
+			Field:   "description",
+			Message: fmt.Sprintf("description must be at most %d characters", limit),
+		}
+	}
+	if limit := s.cfg.MaxProblemStatementLength; limit > 0 && utf8.RuneCountInString(problemStatement) > limit {
+		return &ValidationError{
+			Field:   "problem_statement",
+			Message: fmt.Sprintf("problem_statement must be at most %d characters", limit),
+		}
+	}
+	return nil
+}
+
+// normalizeTags trims and de-duplicates (case-insensitively, first spelling wins) the tags
+// and re-encodes them as a JSON array. Older clients sending "a, b" are accepted too
+func (s *PromptService) normalizeTags(raw string) (string, error) {
+	tags, err := parseTags(raw)
+	if err != nil {
+		return "", err
+	}
+	return s.encodeTags(tags)
+}
+
+// parseTags reads a stored or submitted tags value, a JSON array or the older "a, b" form
+func parseTags(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+		if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			return nil, &ValidationError{Field: "tags", Message: "tags must be an array of strings"}
+		}
+		tags = strings.Split(raw, ",")
+	}
+	return tags, nil
+}
+
+// encodeTags trims, de-duplicates and caps the tags, "" when none are left
+func (s *PromptService) encodeTags(tags []string) (string, error) {
+	seen := make(map[string]bool, len(tags))
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, tag)
+	}
+
+	if limit := s.cfg.MaxTagsPerPrompt; limit > 0 && len(cleaned) > limit {
+		return "", &ValidationError{
+			Field:   "tags",
+			Message: fmt.Sprintf("tags accepts at most %d tags", limit),
+		}
+	}
+	if len(cleaned) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tags: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// BulkTag applies the same tag additions/removals to every listed prompt in one transaction
+// Prompts that would break a tag rule are reported as failed and left as they were, the rest still apply
+func (s *PromptService) BulkTag(req *models.BulkTagRequest) ([]BulkResult, error) {
+	if len(req.IDs) == 0 {
+		return nil, errors.New("ids is required")
+	}
+	if len(req.IDs) > 100 {
+		return nil, errors.New("invalid ids: at most 100 prompts per request")
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return nil, errors.New("add or remove is required")
+	}
+
+	remove := make(map[string]bool, len(req.Remove))
+	for _, tag := range req.Remove {
+		remove[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+
+	results := make(map[uint]BulkResult, len(req.IDs))
+	err := s.promptRepo.UpdateTags(req.IDs, func(prompt *models.Prompt) (string, bool) {
+		current, err := parseTags(prompt.Tags)
+		if err != nil {
+			results[prompt.ID] = BulkResult{ID: prompt.ID, Status: "failed", Error: "stored tags are not a valid list"}
+			return "", false
+		}
+		before, _ := s.encodeTags(current)
+
+		tags := slices.DeleteFunc(current, func(tag string) bool {
+			return remove[strings.ToLower(strings.TrimSpace(tag))]
+		})
+		tags = append(tags, req.Add...)
+
+		encoded, err := s.encodeTags(tags)
+		if err != nil {
+			results[prompt.ID] = BulkResult{ID: prompt.ID, Status: "failed", Value: prompt.Tags, Error: err.Error()}
+			return "", false
+		}
+		if encoded == before {
+			results[prompt.ID] = BulkResult{ID: prompt.ID, Status: "unchanged", Value: encoded}
+			return "", false
+		}
+
+		results[prompt.ID] = BulkResult{ID: prompt.ID, Status: "updated", Value: encoded}
+		return encoded, true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	return orderBulkResults(req.IDs, results), nil
+}
+
+// checkLanguage enforces ALLOWED_LANGUAGES, compared trimmed and case-insensitively
+func (s *PromptService) checkLanguage(language string) error {
+	allowed := s.cfg.AllowedLanguages
+	if len(allowed) == 0 || slices.Contains(allowed, strings.ToLower(strings.TrimSpace(language))) {
+		return nil
+	}
+
+	return &ValidationError{
+		Field:   "language",
+		Message: fmt.Sprintf("language %q is not allowed (allowed: %s)", language, strings.Join(allowed, ", ")),
+	}
+}
+
+// checkCategoryDifficulty enforces the per-category difficulty curation rules
+func (s *PromptService) checkCategoryDifficulty(category string, difficulty models.DifficultyLevel) error {
+	allowed, ok := s.cfg.CategoryDifficulties[strings.ToLower(strings.TrimSpace(category))]
+	if !ok || slices.Contains(allowed, string(difficulty)) {
+		return nil
+	}
+
+	return &ValidationError{
+		Field:   "difficulty",
+		Message: fmt.Sprintf("difficulty %q is not allowed for category %q (allowed: %s)", difficulty, category, strings.Join(allowed, ", ")),
+	}
+}
+
 func (s *PromptService) transformToResponse(prompt *models.Prompt) PromptResponse {
 	return PromptResponse{
 		ID:               prompt.ID,
@@ -193,12 +1213,69 @@ func (s *PromptService) transformToResponse(prompt *models.Prompt) PromptRespons
 		Difficulty:       prompt.Difficulty,
 		Category:         prompt.Category,
 		ProblemStatement: prompt.ProblemStatement,
+		Examples:         prompt.Examples,
+		Hints:            prompt.Hints,
+		EstimatedTime:    prompt.EstimatedTime,
 		IsVerified:       prompt.IsVerified,
 		ViewCount:        prompt.ViewCount,
 		LikeCount:        prompt.LikeCount,
 		Tags:             prompt.Tags,
 		AuthorName:       prompt.AuthorName,
-		CreatedAt:        prompt.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:        prompt.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ExternalID:       stringValue(prompt.ExternalID),
+		QualityScore:     s.qualityScore(prompt, time.Now()),
+		CreatedAt:        formatTimestamp(prompt.CreatedAt),
+		UpdatedAt:        formatTimestamp(prompt.UpdatedAt),
+		ETag:             prompt.ETag(),
+	}
+}
+
+func transformTranslation(translation *models.PromptTranslation) PromptTranslationResponse {
+	return PromptTranslationResponse{
+		Locale:           translation.Locale,
+		Title:            translation.Title,
+		Description:      translation.Description,
+		ProblemStatement: translation.ProblemStatement,
+		UpdatedAt:        formatTimestamp(translation.UpdatedAt),
+	}
+}
+
+// qualityScore ranks a prompt for curation, see models.QualityWeights for the signals
+// Keep in sync with PromptRepository.qualityOrder, which sorts by the same formula in SQL
+func (s *PromptService) qualityScore(prompt *models.Prompt, now time.Time) float64 {
+	w := s.cfg.QualityWeights
+
+	verified := 0.0
+	if prompt.IsVerified {
+		verified = 1
 	}
+
+	engagement := math.Min(float64(prompt.LikeCount)/math.Max(float64(prompt.ViewCount), 1), 1)
+
+	checks := 0.0
+	if strings.TrimSpace(prompt.Examples) != "" {
+		checks++
+	}
+	if strings.TrimSpace(prompt.Hints) != "" {
+		checks++
+	}
+	completeness := checks / 2
+
+	ageDays := math.Max(now.Sub(prompt.CreatedAt).Hours()/24, 0)
+	freshness := 1 / (1 + ageDays/models.QualityFreshnessDays)
+
+	score := w.Verified*verified + w.Engagement*engagement + w.Completeness*completeness + w.Freshness*freshness
+	return math.Round(score*100) / 100
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// formatTimestamp renders times as UTC RFC 3339, e.g. 2024-05-01T12:00:00Z
+// Converting first matters: the old layout stamped a literal Z on local times
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
 }
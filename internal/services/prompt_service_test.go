@@ -0,0 +1,289 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"PromptGallery/internal/testdb"
+	"PromptGallery/internal/worker"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+
+	tests := []struct {
+		name string
+		in   time.Time
+		want string
+	}{
+		{name: "utc", in: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), want: "2024-05-01T12:00:00Z"},
+		{name: "local time is converted", in: time.Date(2024, 5, 1, 19, 0, 0, 0, bangkok), want: "2024-05-01T12:00:00Z"},
+		{name: "sub-second dropped", in: time.Date(2024, 5, 1, 12, 0, 0, 999, time.UTC), want: "2024-05-01T12:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTimestamp(tt.in); got != tt.want {
+				t.Errorf("formatTimestamp() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDifficultyMismatchWarning(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdvancedKeywords = []string{"dynamic programming", "memoization", "mutex"}
+	service := NewPromptService(nil, nil, cfg, nil)
+
+	tests := []struct {
+		name       string
+		difficulty models.DifficultyLevel
+		statement  string
+		want       []string // keywords the warning names, nil for no warning
+	}{
+		{name: "beginner with two keywords", difficulty: models.DifficultyBeginner,
+			statement: "Solve it with Dynamic Programming and memoization.", want: []string{"dynamic programming", "memoization"}},
+		{name: "beginner with one keyword", difficulty: models.DifficultyBeginner,
+			statement: "Use memoization to speed it up."},
+		{name: "repeated keyword counts once", difficulty: models.DifficultyBeginner,
+			statement: "Memoization here, memoization there."},
+		{name: "plain beginner", difficulty: models.DifficultyBeginner,
+			statement: "Return the sum of two numbers."},
+		{name: "advanced prompt", difficulty: models.DifficultyAdvanced,
+			statement: "Guard the cache with a mutex and use dynamic programming."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := service.difficultyMismatchWarning(&models.Prompt{Difficulty: tt.difficulty, ProblemStatement: tt.statement})
+
+			if tt.want == nil {
+				if warning != "" {
+					t.Errorf("warning = %q, want none", warning)
+				}
+				return
+			}
+			if !strings.Contains(warning, "consider reviewing the difficulty") {
+				t.Errorf("warning = %q, want a difficulty nudge", warning)
+			}
+			for _, keyword := range tt.want {
+				if !strings.Contains(warning, keyword) {
+					t.Errorf("warning = %q, want it to name %q", warning, keyword)
+				}
+			}
+		})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	bucket := func(titles ...string) []models.Prompt {
+		prompts := make([]models.Prompt, 0, len(titles))
+		for _, title := range titles {
+			prompts = append(prompts, models.Prompt{Title: title})
+		}
+		return prompts
+	}
+
+	tests := []struct {
+		name    string
+		buckets [][]models.Prompt
+		limit   int
+		want    string
+	}{
+		{name: "round robin", buckets: [][]models.Prompt{bucket("b1", "b2"), bucket("i1", "i2"), bucket("a1", "a2")},
+			limit: 6, want: "b1 i1 a1 b2 i2 a2"},
+		{name: "limit cuts a round short", buckets: [][]models.Prompt{bucket("b1", "b2"), bucket("i1", "i2"), bucket("a1", "a2")},
+			limit: 4, want: "b1 i1 a1 b2"},
+		{name: "others fill in for a short bucket", buckets: [][]models.Prompt{bucket("b1", "b2", "b3"), bucket(), bucket("a1")},
+			limit: 5, want: "b1 a1 b2 b3"},
+		{name: "all empty", buckets: [][]models.Prompt{bucket(), bucket()}, limit: 3, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var titles []string
+			for _, prompt := range interleave(tt.buckets, tt.limit) {
+				titles = append(titles, prompt.Title)
+			}
+			if got := strings.Join(titles, " "); got != tt.want {
+				t.Errorf("interleave() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckContentLength(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxDescriptionLength = 10
+	cfg.MaxProblemStatementLength = 20
+	service := NewPromptService(nil, nil, cfg, nil)
+
+	tests := []struct {
+		name             string
+		description      string
+		problemStatement string
+		wantField        string // "" when the lengths are fine
+	}{
+		{name: "at the limits", description: strings.Repeat("a", 10), problemStatement: strings.Repeat("b", 20)},
+		{name: "characters, not bytes", description: strings.Repeat("é", 10), problemStatement: strings.Repeat("問", 20)},
+		{name: "long description", description: strings.Repeat("a", 11), problemStatement: "ok", wantField: "description"},
+		{name: "long problem statement", description: "ok", problemStatement: strings.Repeat("b", 21), wantField: "problem_statement"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.checkContentLength(tt.description, tt.problemStatement)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("error = %v, want a ValidationError on %s", err, tt.wantField)
+			}
+		})
+	}
+
+	t.Run("zero disables", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.MaxDescriptionLength, cfg.MaxProblemStatementLength = 0, 0
+		service := NewPromptService(nil, nil, cfg, nil)
+
+		if err := service.checkContentLength(strings.Repeat("a", 100000), strings.Repeat("b", 100000)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("create requests are checked", func(t *testing.T) {
+		req := &models.PromptCreateRequest{
+			Title:            "Two Sum",
+			Description:      strings.Repeat("a", 11),
+			Language:         "go",
+			Category:         "algorithms",
+			ProblemStatement: "Return the indices of the two numbers that add up to target.",
+		}
+
+		var validationErr *ValidationError
+		if err := service.validateCreateRequest(req); !errors.As(err, &validationErr) || validationErr.Field != "description" {
+			t.Errorf("error = %v, want a ValidationError on description", err)
+		}
+	})
+}
+
+func TestGuardSearch(t *testing.T) {
+	cfg := testConfig()
+	cfg.MinSearchLength = 3
+	service := NewPromptService(nil, nil, cfg, nil)
+
+	tests := []struct {
+		name       string
+		search     string
+		wantSearch string
+		wantNotice bool
+	}{
+		{name: "no search", search: "", wantSearch: ""},
+		{name: "long enough", search: "sum", wantSearch: "sum"},
+		{name: "trimmed first", search: "  sum  ", wantSearch: "sum"},
+		{name: "too short", search: "su", wantSearch: "", wantNotice: true},
+		{name: "padding doesn't count", search: " a ", wantSearch: "", wantNotice: true},
+		{name: "characters, not bytes", search: "日本語", wantSearch: "日本語"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := models.PromptFilter{Search: tt.search}
+			notice := service.guardSearch(&filter)
+
+			if filter.Search != tt.wantSearch {
+				t.Errorf("search = %q, want %q", filter.Search, tt.wantSearch)
+			}
+			if (notice != "") != tt.wantNotice {
+				t.Errorf("notice = %q, want one: %v", notice, tt.wantNotice)
+			}
+			if tt.wantNotice && !strings.Contains(notice, "at least 3 characters") {
+				t.Errorf("notice = %q, want it to give the minimum", notice)
+			}
+		})
+	}
+}
+
+func TestViewCountsGoThroughThePool(t *testing.T) {
+	s := newTestServices(t, testConfig())
+	promptRepo := repositories.NewPromptRepository(s.db, "", models.QualityWeights{})
+	prompt := testdb.SeedPrompt(t, s.db, nil)
+
+	const reads = 500
+
+	tests := []struct {
+		name      string
+		queueSize int
+		wantAll   bool // every view is counted, otherwise some are dropped on a full queue
+	}{
+		{name: "room for every view", queueSize: reads, wantAll: true},
+		{name: "full queue drops views", queueSize: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.db.Where("prompt_id = ?", prompt.ID).Delete(&models.PromptCount{})
+			before := runtime.NumGoroutine()
+
+			tasks := worker.NewPool(2, tt.queueSize)
+			service := NewPromptService(promptRepo, nil, s.cfg, tasks)
+			for i := 0; i < reads; i++ {
+				if _, err := service.GetPromptByID(prompt.ID, PromptDetailOptions{}); err != nil {
+					t.Fatalf("read %d: %v", i+1, err)
+				}
+			}
+			tasks.Stop()
+
+			// Stop has waited for the workers, only the runtime may still be winding them down
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if after := runtime.NumGoroutine(); after > before {
+				t.Errorf("%d goroutines after the reads, %d before", after, before)
+			}
+
+			var counts models.PromptCount
+			s.db.Limit(1).Find(&counts, "prompt_id = ?", prompt.ID)
+			if tt.wantAll && counts.ViewCount != reads {
+				t.Errorf("view_count = %d, want %d", counts.ViewCount, reads)
+			}
+			if !tt.wantAll && (counts.ViewCount < 1 || counts.ViewCount > reads) {
+				t.Errorf("view_count = %d, want between 1 and %d", counts.ViewCount, reads)
+			}
+		})
+	}
+}
+
+func TestUnverifyPromptRequiresReason(t *testing.T) {
+	service := NewPromptService(nil, nil, testConfig(), nil)
+
+	tests := []struct {
+		name    string
+		reason  string
+		wantErr string
+	}{
+		{name: "missing", reason: "", wantErr: "reason is required"},
+		{name: "blank", reason: " \n\t", wantErr: "reason is required"},
+		{name: "too long", reason: strings.Repeat("a", models.MaxAuditReasonLength+1), wantErr: "invalid reason"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UnverifyPrompt(1, 1, tt.reason)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
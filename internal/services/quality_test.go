@@ -0,0 +1,37 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"testing"
+	"time"
+)
+
+func TestQualityScore(t *testing.T) {
+	s := &PromptService{cfg: &config.Config{QualityWeights: models.QualityWeights{
+		Verified: 40, Engagement: 30, Completeness: 20, Freshness: 10,
+	}}}
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	monthOld := now.AddDate(0, 0, -30)
+
+	tests := []struct {
+		name   string
+		prompt models.Prompt
+		want   float64
+	}{
+		{name: "bare", prompt: models.Prompt{Model: models.Model{CreatedAt: monthOld}}, want: 5},
+		{name: "examples only", prompt: models.Prompt{Model: models.Model{CreatedAt: monthOld}, Examples: "in: [1,2] out: 3"}, want: 15},
+		{name: "blank hints don't count", prompt: models.Prompt{Model: models.Model{CreatedAt: monthOld}, Examples: "x", Hints: "  \n"}, want: 15},
+		{name: "examples and hints", prompt: models.Prompt{Model: models.Model{CreatedAt: monthOld}, Examples: "x", Hints: "use a map"}, want: 25},
+		{name: "everything, brand new", prompt: models.Prompt{Model: models.Model{CreatedAt: now}, IsVerified: true, LikeCount: 5, ViewCount: 5, Examples: "x", Hints: "y"}, want: 100},
+		{name: "engagement capped", prompt: models.Prompt{Model: models.Model{CreatedAt: monthOld}, LikeCount: 10, ViewCount: 2}, want: 35},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.qualityScore(&tt.prompt, now); got != tt.want {
+				t.Errorf("qualityScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
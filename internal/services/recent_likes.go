@@ -0,0 +1,47 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// recentLikes remembers which anonymous callers liked which prompt within the dedup window
+// It lives in process memory, so each instance dedups on its own and a restart forgets
+type recentLikes struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+func newRecentLikes() *recentLikes {
+	return &recentLikes{seen: make(map[string]time.Time)}
+}
+
+// claim records key and reports true unless it was already claimed within window
+func (r *recentLikes) claim(key string, window time.Duration, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop expired keys once per window so the map doesn't grow with every IP ever seen
+	if now.Sub(r.lastPrune) >= window {
+		for k, at := range r.seen {
+			if now.Sub(at) >= window {
+				delete(r.seen, k)
+			}
+		}
+		r.lastPrune = now
+	}
+
+	if at, ok := r.seen[key]; ok && now.Sub(at) < window {
+		return false
+	}
+	r.seen[key] = now
+	return true
+}
+
+// release forgets key, used when the like it guarded wasn't stored
+func (r *recentLikes) release(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.seen, key)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecentLikesClaim(t *testing.T) {
+	r := newRecentLikes()
+	window := time.Minute
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if !r.claim("1.2.3.4|7", window, start) {
+		t.Fatal("first like refused")
+	}
+	if r.claim("1.2.3.4|7", window, start.Add(59*time.Second)) {
+		t.Error("repeat within the window accepted")
+	}
+	if !r.claim("1.2.3.4|8", window, start.Add(time.Second)) {
+		t.Error("same IP, other prompt refused")
+	}
+	if !r.claim("5.6.7.8|7", window, start.Add(time.Second)) {
+		t.Error("other IP, same prompt refused")
+	}
+	if !r.claim("1.2.3.4|7", window, start.Add(window)) {
+		t.Error("repeat after the window refused")
+	}
+}
+
+func TestRecentLikesRelease(t *testing.T) {
+	r := newRecentLikes()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	r.claim("1.2.3.4|7", time.Minute, now)
+	r.release("1.2.3.4|7")
+
+	if !r.claim("1.2.3.4|7", time.Minute, now.Add(time.Second)) {
+		t.Error("released key still blocks a retry")
+	}
+	r.release("never-claimed") // must not panic
+}
+
+func TestRecentLikesPrunesExpiredKeys(t *testing.T) {
+	r := newRecentLikes()
+	window := time.Minute
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, key := range []string{"a", "b", "c"} {
+		r.claim(key, window, start)
+	}
+	r.claim("d", window, start.Add(2*window))
+
+	if len(r.seen) != 1 {
+		t.Errorf("%d keys kept after the window, want only the fresh one", len(r.seen))
+	}
+}
@@ -1 +1,329 @@
 package services
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+type PromptRequestService struct {
+	requestRepo *repositories.PromptRequestRepository
+}
+
+func NewPromptRequestService(requestRepo *repositories.PromptRequestRepository) *PromptRequestService {
+	return &PromptRequestService{
+		requestRepo: requestRepo,
+	}
+}
+
+// PaginationRequestResponse is the admin request list, full requests including admin fields
+type PaginationRequestResponse struct {
+	Data       []models.PromptRequest `json:"data"`
+	Total      int64                  `json:"total"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// CreateRequest stores a public prompt request, it always starts pending
+func (s *PromptRequestService) CreateRequest(req *models.PromptRequestCreateRequest) (*models.PromptRequestResponse, error) {
+	if err := validateRequestSubmission(req); err != nil {
+		return nil, err
+	}
+
+	request := req.ToPromptRequest()
+	request.RequesterEmail = models.NormalizeEmail(request.RequesterEmail)
+
+	createdRequest, err := s.requestRepo.Create(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return createdRequest.ToResponse(), nil
+}
+
+// GetRequests pages through requests for the admin panel
+func (s *PromptRequestService) GetRequests(filter models.RequestFilter, pagination models.PaginationParams) (*PaginationRequestResponse, error) {
+	pagination.Normalize()
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, errors.New("invalid status")
+	}
+	if filter.Priority != "" && !filter.Priority.Valid() {
+		return nil, errors.New("invalid priority")
+	}
+	if filter.RequestedDifficulty != "" && !filter.RequestedDifficulty.Valid() {
+		return nil, errors.New("invalid difficulty")
+	}
+
+	requests, total, err := s.requestRepo.FindAll(filter, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find requests: %w", err)
+	}
+
+	return &PaginationRequestResponse{
+		Data:       requests,
+		Total:      total,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalPages: pagination.TotalPages(total),
+	}, nil
+}
+
+// UpdateRequest applies an admin's changes to a request, only the fields sent are changed
+// Assignment and completion are stamped the first time they're set, moving to completed stamps
+// CompletedAt too. A status change the workflow doesn't allow is refused as a conflict
+func (s *PromptRequestService) UpdateRequest(id uint, req *models.PromptRequestUpdateRequest, adminID uint) (*models.PromptRequest, error) {
+	if id == 0 {
+		return nil, errors.New("invalid request id")
+	}
+	if req.Status != nil && !req.Status.Valid() {
+		return nil, errors.New("invalid status")
+	}
+	if req.Priority != nil && !req.Priority.Valid() {
+		return nil, errors.New("invalid priority")
+	}
+	if req.EstimatedHours != nil && *req.EstimatedHours < 0 {
+		return nil, errors.New("invalid estimated hours: must not be negative")
+	}
+
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+
+	if req.Status != nil {
+		if !request.Status.CanTransitionTo(*req.Status) {
+			return nil, fmt.Errorf("status transition not allowed: %s -> %s", request.Status, *req.Status)
+		}
+		request.Status = *req.Status
+		request.IsRejected = request.Status == models.StatusRejected
+		if request.Status == models.StatusCompleted && request.CompletedAt == nil {
+			request.CompletedAt = &now
+		}
+	}
+	if req.Priority != nil {
+		request.Priority = *req.Priority
+	}
+	if req.AssignedToID != nil {
+		if request.AssignedToID == nil {
+			request.AssignedAt = &now
+		}
+		request.AssignedToID = req.AssignedToID
+		request.AssignedBy = &adminID
+	}
+	if req.CompletedPromptID != nil {
+		if request.CompletedPromptID == nil && request.CompletedAt == nil {
+			request.CompletedAt = &now
+		}
+		request.CompletedPromptID = req.CompletedPromptID
+	}
+	if req.AdminNotes != nil {
+		request.AdminNotes = *req.AdminNotes
+	}
+	if req.ResponseMessage != nil {
+		request.ResponseMessage = *req.ResponseMessage
+	}
+	if req.EstimatedHours != nil {
+		request.EstimatedHours = *req.EstimatedHours
+	}
+
+	updatedRequest, err := s.requestRepo.Update(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update request: %w", err)
+	}
+	return updatedRequest, nil
+}
+
+// MarkDuplicate rejects request id as a duplicate of the canonical request
+// The canonical request must exist and must not itself be a duplicate, so links never chain
+func (s *PromptRequestService) MarkDuplicate(id uint, req *models.DuplicateOfRequest) (*models.PromptRequest, error) {
+	if id == 0 {
+		return nil, errors.New("invalid request id")
+	}
+	if req.DuplicateOfID == 0 {
+		return nil, errors.New("duplicate_of_id is required")
+	}
+	if req.DuplicateOfID == id {
+		return nil, errors.New("invalid duplicate_of_id: a request can't duplicate itself")
+	}
+
+	request, err := s.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical, err := s.requestRepo.FindByID(req.DuplicateOfID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("canonical request not found")
+		}
+		return nil, err
+	}
+	if canonical.DuplicateOfID != nil {
+		return nil, fmt.Errorf("invalid duplicate_of_id: request #%d is itself a duplicate of #%d", canonical.ID, *canonical.DuplicateOfID)
+	}
+
+	if err := request.MarkDuplicateOf(canonical); err != nil {
+		return nil, err
+	}
+
+	updatedRequest, err := s.requestRepo.Update(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update request: %w", err)
+	}
+	return updatedRequest, nil
+}
+
+func validateRequestSubmission(req *models.PromptRequestCreateRequest) error {
+	fields := []struct {
+		name, value string
+		max         int
+	}{
+		{"requester_name", req.RequesterName, 100},
+		{"requester_email", req.RequesterEmail, 100},
+		{"requested_title", req.RequestedTitle, 200},
+		{"requested_language", req.RequestedLanguage, 50},
+		{"requested_category", req.RequestedCategory, 100},
+		{"description", req.Description, 0},
+	}
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			return &ValidationError{Field: field.name, Message: field.name + " is required"}
+		}
+		if field.max > 0 && len(field.value) > field.max {
+			return &ValidationError{Field: field.name, Message: fmt.Sprintf("%s must be at most %d characters", field.name, field.max)}
+		}
+	}
+
+	if _, err := mail.ParseAddress(req.RequesterEmail); err != nil {
+		return &ValidationError{Field: "requester_email", Message: "requester_email must be a valid email address"}
+	}
+	if req.RequestedDifficulty == "" {
+		return &ValidationError{Field: "requested_difficulty", Message: "requested_difficulty is required"}
+	}
+	if !req.RequestedDifficulty.Valid() {
+		return &ValidationError{Field: "requested_difficulty", Message: "invalid requested_difficulty"}
+	}
+
+	return nil
+}
+
+// CompletedRequestResponse is the public changelog entry for a fulfilled request
+// Requester details and admin fields are left out, it only says what was asked for and where it landed
+type CompletedRequestResponse struct {
+	ID                  uint                   `json:"id"`
+	RequestedTitle      string                 `json:"requested_title"`
+	RequestedLanguage   string                 `json:"requested_language"`
+	RequestedDifficulty models.DifficultyLevel `json:"requested_difficulty"`
+	RequestedCategory   string                 `json:"requested_category"`
+	CompletedPromptID   *uint                  `json:"completed_prompt_id,omitempty"`
+	CompletedAt         string                 `json:"completed_at,omitempty"`
+}
+
+type PaginationCompletedRequestResponse struct {
+	Data       []CompletedRequestResponse `json:"data"`
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	Limit      int                        `json:"limit"`
+	TotalPages int                        `json:"total_pages"`
+}
+
+// GetCompletedRequests lists fulfilled requests newest first, the community changelog
+func (s *PromptRequestService) GetCompletedRequests(pagination models.PaginationParams) (*PaginationCompletedRequestResponse, error) {
+	pagination.Normalize()
+
+	requests, total, err := s.requestRepo.FindCompleted(pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find completed requests: %w", err)
+	}
+
+	responses := make([]CompletedRequestResponse, len(requests))
+	for i := range requests {
+		responses[i] = transformCompletedRequest(&requests[i])
+	}
+
+	return &PaginationCompletedRequestResponse{
+		Data:       responses,
+		Total:      total,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalPages: pagination.TotalPages(total),
+	}, nil
+}
+
+// GetSourceRequests returns the requests a prompt fulfilled
+// Request managers get the full requests, everyone else the public changelog shape
+func (s *PromptRequestService) GetSourceRequests(promptID uint, full bool) (interface{}, int, error) {
+	if promptID == 0 {
+		return nil, 0, errors.New("invalid prompt id")
+	}
+
+	requests, err := s.requestRepo.FindByCompletedPrompt(promptID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find source requests: %w", err)
+	}
+
+	if full {
+		return requests, len(requests), nil
+	}
+
+	responses := make([]CompletedRequestResponse, len(requests))
+	for i := range requests {
+		responses[i] = transformCompletedRequest(&requests[i])
+	}
+	return responses, len(responses), nil
+}
+
+func transformCompletedRequest(request *models.PromptRequest) CompletedRequestResponse {
+	response := CompletedRequestResponse{
+		ID:                  request.ID,
+		RequestedTitle:      request.RequestedTitle,
+		RequestedLanguage:   request.RequestedLanguage,
+		RequestedDifficulty: request.RequestedDifficulty,
+		RequestedCategory:   request.RequestedCategory,
+		CompletedPromptID:   request.CompletedPromptID,
+	}
+	if request.CompletedAt != nil {
+		response.CompletedAt = formatTimestamp(time.Unix(*request.CompletedAt, 0))
+	}
+	return response
+}
+
+// BulkSetPriority moves every listed request to the same priority in one transaction
+// Each request that actually changes gets an audit entry naming actorID and the old and new priority
+func (s *PromptRequestService) BulkSetPriority(req *models.BulkPriorityRequest, actorID uint) ([]BulkResult, error) {
+	if len(req.IDs) == 0 {
+		return nil, errors.New("ids is required")
+	}
+	if len(req.IDs) > 100 {
+		return nil, errors.New("invalid ids: at most 100 requests per call")
+	}
+	if req.Priority == "" {
+		return nil, errors.New("priority is required")
+	}
+	if !req.Priority.Valid() {
+		return nil, errors.New("invalid priority")
+	}
+
+	before, err := s.requestRepo.SetPriority(req.IDs, req.Priority, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update priorities: %w", err)
+	}
+
+	results := make(map[uint]BulkResult, len(before))
+	for _, request := range before {
+		status := "updated"
+		if request.Priority == req.Priority {
+			status = "unchanged"
+		}
+		results[request.ID] = BulkResult{ID: request.ID, Status: status, Value: string(req.Priority)}
+	}
+	return orderBulkResults(req.IDs, results), nil
+}
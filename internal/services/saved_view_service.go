@@ -0,0 +1,180 @@
+package services
+
+import (
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type SavedViewService struct {
+	viewRepo *repositories.SavedViewRepository
+}
+
+func NewSavedViewService(viewRepo *repositories.SavedViewRepository) *SavedViewService {
+	return &SavedViewService{
+		viewRepo: viewRepo,
+	}
+}
+
+type SavedViewResponse struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	Filter    models.PromptFilter `json:"filter"`
+	CreatedAt string              `json:"created_at"`
+	UpdatedAt string              `json:"updated_at"`
+}
+
+func (s *SavedViewService) ListViews(userID uint) ([]SavedViewResponse, error) {
+	views, err := s.viewRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find views: %w", err)
+	}
+
+	responses := make([]SavedViewResponse, len(views))
+	for i := range views {
+		responses[i] = transformSavedView(&views[i])
+	}
+	return responses, nil
+}
+
+func (s *SavedViewService) GetView(id, userID uint) (*SavedViewResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid view id")
+	}
+
+	view, err := s.viewRepo.FindForUser(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := transformSavedView(view)
+	return &response, nil
+}
+
+func (s *SavedViewService) CreateView(userID uint, req *models.SavedViewRequest) (*SavedViewResponse, error) {
+	if err := s.validateRequest(userID, 0, req); err != nil {
+		return nil, err
+	}
+
+	view := &models.SavedView{UserID: userID, Name: strings.TrimSpace(req.Name)}
+	if err := view.SetFilter(req.Filter); err != nil {
+		return nil, fmt.Errorf("failed to encode filter: %w", err)
+	}
+
+	createdView, err := s.viewRepo.Create(view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create view: %w", err)
+	}
+
+	response := transformSavedView(createdView)
+	return &response, nil
+}
+
+// UpdateView replaces the name and filter of one of the user's views
+func (s *SavedViewService) UpdateView(id, userID uint, req *models.SavedViewRequest) (*SavedViewResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid view id")
+	}
+
+	view, err := s.viewRepo.FindForUser(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRequest(userID, id, req); err != nil {
+		return nil, err
+	}
+
+	view.Name = strings.TrimSpace(req.Name)
+	if err := view.SetFilter(req.Filter); err != nil {
+		return nil, fmt.Errorf("failed to encode filter: %w", err)
+	}
+
+	updatedView, err := s.viewRepo.Update(view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update view: %w", err)
+	}
+
+	response := transformSavedView(updatedView)
+	return &response, nil
+}
+
+func (s *SavedViewService) DeleteView(id, userID uint) error {
+	if id == 0 {
+		return errors.New("invalid view id")
+	}
+
+	if err := s.viewRepo.Delete(id, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+// FilterFor returns the stored filter of one of the user's views, ready for GetAllPrompts
+func (s *SavedViewService) FilterFor(id, userID uint) (models.PromptFilter, error) {
+	if id == 0 {
+		return models.PromptFilter{}, errors.New("invalid view id")
+	}
+
+	view, err := s.viewRepo.FindForUser(id, userID)
+	if err != nil {
+		return models.PromptFilter{}, err
+	}
+
+	return view.GetFilter(), nil
+}
+
+func (s *SavedViewService) validateRequest(userID, id uint, req *models.SavedViewRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("invalid name: must be less than 100 characters")
+	}
+
+	filter := req.Filter
+	if filter.Difficulty != "" && !filter.Difficulty.Valid() {
+		return errors.New("invalid difficulty")
+	}
+	if filter.Sort != "" && !filter.Sort.Valid() {
+		return errors.New("invalid sort option")
+	}
+	if len(filter.IDs) > 100 {
+		return errors.New("invalid ids: at most 100 ids")
+	}
+	if filter.Ordered && len(filter.IDs) == 0 {
+		return errors.New("invalid filter: ordered requires ids")
+	}
+	if len(filter.Seed) > models.MaxSeedLength {
+		return errors.New("invalid seed: too long")
+	}
+	if filter.Seed != "" && (filter.Sort != "" || filter.Ordered) {
+		return errors.New("invalid filter: seed can't be combined with sort or ordered")
+	}
+
+	taken, err := s.viewRepo.NameTaken(userID, name, id)
+	if err != nil {
+		return fmt.Errorf("failed to check view name: %w", err)
+	}
+	if taken {
+		return errors.New("a view with this name already exists")
+	}
+
+	return nil
+}
+
+func transformSavedView(view *models.SavedView) SavedViewResponse {
+	return SavedViewResponse{
+		ID:        view.ID,
+		Name:      view.Name,
+		Filter:    view.GetFilter(),
+		CreatedAt: formatTimestamp(view.CreatedAt),
+		UpdatedAt: formatTimestamp(view.UpdatedAt),
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SitemapMaxURLs is the sitemaps.org limit per file, past it the sitemap becomes an index
+	SitemapMaxURLs = 50000
+
+	sitemapChunkSize = 5000 // rows loaded per query while building a sitemap
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// SitemapPageCount is how many sitemap files the verified prompts need, 1 means no index
+func (s *PromptService) SitemapPageCount() (int, error) {
+	total, err := s.promptRepo.CountVerified()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count verified prompts: %w", err)
+	}
+	return sitemapPages(total), nil
+}
+
+// sitemapPages is how many files of SitemapMaxURLs hold total URLs, never fewer than one
+func sitemapPages(total int64) int {
+	return max(int((total+SitemapMaxURLs-1)/SitemapMaxURLs), 1)
+}
+
+// RenderSitemap renders page (1-based) of the verified prompts as a <urlset>
+// Each prompt links to siteURL/prompts/:id with its updated_at as lastmod
+func (s *PromptService) RenderSitemap(siteURL string, page int) ([]byte, error) {
+	if page < 1 {
+		return nil, errors.New("invalid sitemap page")
+	}
+
+	urlSet := sitemapURLSet{Xmlns: sitemapNamespace, URLs: []sitemapURL{}}
+	siteURL = strings.TrimSuffix(siteURL, "/")
+
+	start := (page - 1) * SitemapMaxURLs
+	for offset := start; offset < start+SitemapMaxURLs; offset += sitemapChunkSize {
+		prompts, err := s.promptRepo.FindVerifiedForSitemap(offset, min(sitemapChunkSize, start+SitemapMaxURLs-offset))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sitemap prompts: %w", err)
+		}
+		for _, prompt := range prompts {
+			urlSet.URLs = append(urlSet.URLs, sitemapURL{
+				Loc:     fmt.Sprintf("%s/prompts/%d", siteURL, prompt.ID),
+				LastMod: formatTimestamp(prompt.UpdatedAt),
+			})
+		}
+		if len(prompts) < sitemapChunkSize {
+			break
+		}
+	}
+
+	if page > 1 && len(urlSet.URLs) == 0 {
+		return nil, errors.New("sitemap page not found")
+	}
+	return marshalSitemap(urlSet)
+}
+
+// RenderSitemapIndex lists the numbered sitemap files, served from apiURL/sitemap-N.xml
+func RenderSitemapIndex(apiURL string, pages int) ([]byte, error) {
+	index := sitemapIndex{Xmlns: sitemapNamespace}
+	apiURL = strings.TrimSuffix(apiURL, "/")
+
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{Loc: fmt.Sprintf("%s/sitemap-%d.xml", apiURL, page)})
+	}
+	return marshalSitemap(index)
+}
+
+func marshalSitemap(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sitemap: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSitemapPages(t *testing.T) {
+	tests := map[int64]int{
+		0:                      1,
+		1:                      1,
+		SitemapMaxURLs:         1,
+		SitemapMaxURLs + 1:     2,
+		3*SitemapMaxURLs - 1:   3,
+		3 * SitemapMaxURLs:     3,
+		3*SitemapMaxURLs + 100: 4,
+	}
+
+	for total, want := range tests {
+		if got := sitemapPages(total); got != want {
+			t.Errorf("sitemapPages(%d) = %d, want %d", total, got, want)
+		}
+	}
+}
+
+func TestRenderSitemapIndex(t *testing.T) {
+	body, err := RenderSitemapIndex("https://api.example.com/", 3)
+	if err != nil {
+		t.Fatalf("RenderSitemapIndex: %v", err)
+	}
+
+	if !strings.HasPrefix(string(body), xml.Header) {
+		t.Error("missing XML declaration")
+	}
+
+	var index sitemapIndex
+	if err := xml.Unmarshal(body, &index); err != nil {
+		t.Fatalf("output isn't valid XML: %v", err)
+	}
+	if index.Xmlns != sitemapNamespace {
+		t.Errorf("xmlns = %q", index.Xmlns)
+	}
+
+	want := []string{
+		"https://api.example.com/sitemap-1.xml",
+		"https://api.example.com/sitemap-2.xml",
+		"https://api.example.com/sitemap-3.xml",
+	}
+	if len(index.Sitemaps) != len(want) {
+		t.Fatalf("%d sitemaps listed, want %d", len(index.Sitemaps), len(want))
+	}
+	for i, sitemap := range index.Sitemaps {
+		if sitemap.Loc != want[i] {
+			t.Errorf("sitemap %d = %q, want %q", i+1, sitemap.Loc, want[i])
+		}
+	}
+}
+
+func TestMarshalSitemapEscapes(t *testing.T) {
+	body, err := marshalSitemap(sitemapURLSet{Xmlns: sitemapNamespace, URLs: []sitemapURL{{Loc: "https://example.com/prompts/1?a=1&b=2"}}})
+	if err != nil {
+		t.Fatalf("marshalSitemap: %v", err)
+	}
+	if !strings.Contains(string(body), "a=1&amp;b=2") {
+		t.Errorf("ampersand not escaped:\n%s", body)
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	s := &PromptService{cfg: &config.Config{MaxTagsPerPrompt: 3}}
+
+	tests := []struct {
+		name      string
+		raw       string
+		want      string
+		wantError bool
+	}{
+		{name: "empty", raw: "  ", want: ""},
+		{name: "json array", raw: `["go", "arrays"]`, want: `["go","arrays"]`},
+		{name: "legacy comma list", raw: "go, arrays ,", want: `["go","arrays"]`},
+		{name: "trimmed", raw: `["  go  "]`, want: `["go"]`},
+		{name: "dedupe keeps first spelling", raw: `["Go", "go", "GO", "maps"]`, want: `["Go","maps"]`},
+		{name: "only blanks", raw: `["", "  "]`, want: ""},
+		{name: "duplicates don't count toward the cap", raw: `["a", "A", "b", "c"]`, want: `["a","b","c"]`},
+		{name: "over the cap", raw: `["a", "b", "c", "d"]`, wantError: true},
+		{name: "broken json array", raw: `["go", 5]`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.normalizeTags(tt.raw)
+			if tt.wantError {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != "tags" {
+					t.Errorf("error = %v, want a tags validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagsWithoutCap(t *testing.T) {
+	s := &PromptService{cfg: &config.Config{}}
+
+	if _, err := s.normalizeTags(`["a","b","c","d","e","f","g","h","i","j","k","l"]`); err != nil {
+		t.Errorf("MAX_TAGS_PER_PROMPT=0 should not cap: %v", err)
+	}
+}
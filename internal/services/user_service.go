@@ -0,0 +1,502 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/repositories"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UserService struct {
+	userRepo    *repositories.UserRepository
+	promptRepo  *repositories.PromptRepository        // authored prompt stats on profiles
+	requestRepo *repositories.PromptRequestRepository // handled requests on the activity summary
+	cfg         *config.Config
+
+	failedLogins  *failedLogins // sign-in failures per client IP, for throttling
+	dummyUserOnce sync.Once
+	dummyUser     models.User // password checked against for unknown logins, so they take as long as wrong passwords
+}
+
+func NewUserService(userRepo *repositories.UserRepository, promptRepo *repositories.PromptRepository, requestRepo *repositories.PromptRequestRepository, cfg *config.Config) *UserService {
+	return &UserService{
+		userRepo:     userRepo,
+		promptRepo:   promptRepo,
+		requestRepo:  requestRepo,
+		cfg:          cfg,
+		failedLogins: newFailedLogins(),
+	}
+}
+
+// PaginationUserResponse is the user list, each entry shaped for the viewer like a profile
+type PaginationUserResponse struct {
+	Data       []interface{} `json:"data"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// CreateUser registers an account, creator is nil for self-registration
+// Only admins may pick the role or use an email outside ALLOWED_EMAIL_DOMAINS
+func (s *UserService) CreateUser(req *models.UserCreateRequest, creator *models.User) (*models.UserResponse, error) {
+	isAdmin := creator != nil && creator.Role.CanManageUsers()
+
+	if err := validateUserCreate(req); err != nil {
+		return nil, err
+	}
+	if req.Role != "" && !isAdmin {
+		return nil, errors.New("role not allowed: only admins can set a role")
+	}
+	if !isAdmin {
+		if err := s.checkEmailDomain(req.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	user := req.ToUser()
+	user.IsActive = true
+	if err := user.SetPassword(req.Password, s.cfg.BcryptCost); err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	createdUser, err := s.userRepo.Create(user)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return createdUser.ToResponse(), nil
+}
+
+// TokenResponse is a newly issued API token, the only time the raw token is returned
+type TokenResponse struct {
+	Token     string    `json:"token"`
+	Name      string    `json:"name,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IssueToken signs a user in by email or username and password and issues a bearer token
+// Unknown logins, wrong passwords and inactive accounts all return ErrInvalidCredentials after the
+// same bcrypt work. clientIP is throttled to LOGIN_MAX_FAILURES failures per window (ErrTooManyAttempts)
+func (s *UserService) IssueToken(req *models.TokenCreateRequest, clientIP string) (*TokenResponse, error) {
+	if strings.TrimSpace(req.Login) == "" {
+		return nil, errors.New("login is required")
+	}
+	if req.Password == "" {
+		return nil, errors.New("password is required")
+	}
+	if len(req.Name) > 100 {
+		return nil, errors.New("invalid name: must be at most 100 characters")
+	}
+
+	now := time.Now()
+	window := time.Duration(s.cfg.LoginFailureWindowSeconds) * time.Second
+	if _, blocked := s.failedLogins.blocked(clientIP, s.cfg.LoginMaxFailures, window, now); blocked {
+		return nil, ErrTooManyAttempts
+	}
+
+	user, err := s.findByLogin(strings.TrimSpace(req.Login))
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		s.dummyUserOnce.Do(func() { s.dummyUser.SetPassword("dummy password", s.cfg.BcryptCost) })
+		s.dummyUser.CheckPassword(req.Password)
+	}
+	if user == nil || !user.CheckPassword(req.Password) || !user.IsActive {
+		s.failedLogins.fail(clientIP, window, now)
+		return nil, ErrInvalidCredentials
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := "pg_" + base64.RawURLEncoding.EncodeToString(raw)
+
+	stored := &models.APIToken{
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: models.HashToken(token),
+		ExpiresAt: now.Add(time.Duration(s.cfg.APITokenTTLHours) * time.Hour),
+	}
+	if err := s.userRepo.CreateToken(stored); err != nil {
+		return nil, fmt.Errorf("failed to store token: %w", err)
+	}
+
+	return &TokenResponse{Token: token, Name: stored.Name, ExpiresAt: stored.ExpiresAt}, nil
+}
+
+// findByLogin looks a user up by email when login has an @, by username otherwise
+func (s *UserService) findByLogin(login string) (*models.User, error) {
+	if strings.Contains(login, "@") {
+		return s.userRepo.FindByEmail(login)
+	}
+	return s.userRepo.FindByUsername(login)
+}
+
+// UserForToken returns the active user a bearer token was issued to, ErrInvalidToken when
+// the token is unknown, revoked or expired. One indexed lookup, no password hashing
+func (s *UserService) UserForToken(token string) (*models.User, error) {
+	user, err := s.userRepo.FindByTokenHash(models.HashToken(token), time.Now())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to look up token: %w", err)
+	}
+	return user, nil
+}
+
+// RevokeToken deletes a bearer token so it can't be used again
+func (s *UserService) RevokeToken(token string) error {
+	deleted, err := s.userRepo.DeleteToken(models.HashToken(token))
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if !deleted {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// GetUser looks up a profile by id, shaped for the viewer like GetProfileByUsername
+func (s *UserService) GetUser(id uint, viewer *models.User) (interface{}, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user id")
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return s.profileFor(user, viewer)
+}
+
+// GetUsers pages through users, inactive accounts are only listed for admins
+func (s *UserService) GetUsers(filter models.UserFilter, pagination models.PaginationParams, viewer *models.User) (*PaginationUserResponse, error) {
+	pagination.Normalize()
+
+	if filter.Role != "" && !filter.Role.Valid() {
+		return nil, errors.New("invalid role")
+	}
+	filter.IncludeInactive = viewer != nil && viewer.Role.CanManageUsers()
+
+	users, total, err := s.userRepo.FindAll(filter, pagination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+
+	data := make([]interface{}, len(users))
+	for i := range users {
+		data[i] = users[i].ResponseFor(viewer)
+	}
+
+	return &PaginationUserResponse{
+		Data:       data,
+		Total:      total,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalPages: pagination.TotalPages(total),
+	}, nil
+}
+
+// UpdateUser applies a profile update, only the fields sent are changed
+// The caller must be the user or an admin, checked by the handler
+func (s *UserService) UpdateUser(id uint, req *models.UserUpdateRequest, editor *models.User) (*models.UserResponse, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if err := validateUserUpdate(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil && !editor.Role.CanManageUsers() && models.NormalizeEmail(*req.Email) != user.Email {
+		if err := s.checkEmailDomain(*req.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := req.ApplyTo(user); err != nil {
+		return nil, fmt.Errorf("failed to set specialties: %w", err)
+	}
+
+	updatedUser, err := s.userRepo.Update(user)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return updatedUser.ToResponse(), nil
+}
+
+// checkEmailDomain enforces ALLOWED_EMAIL_DOMAINS, an empty list allows every domain
+func (s *UserService) checkEmailDomain(email string) error {
+	if len(s.cfg.AllowedEmailDomains) == 0 {
+		return nil
+	}
+
+	email = models.NormalizeEmail(email)
+	domain := email[strings.LastIndex(email, "@")+1:]
+	if !slices.Contains(s.cfg.AllowedEmailDomains, domain) {
+		return fmt.Errorf("email domain not allowed: %s", domain)
+	}
+	return nil
+}
+
+func validateUserCreate(req *models.UserCreateRequest) error {
+	if err := validateUserUpdate(&models.UserUpdateRequest{
+		Name:     &req.Name,
+		Email:    &req.Email,
+		Username: &req.Username,
+		Bio:      &req.Bio,
+		Website:  &req.Website,
+	}); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(req.Password) == "" {
+		return &ValidationError{Field: "password", Message: "password is required"}
+	}
+	if len(req.Password) < 8 {
+		return &ValidationError{Field: "password", Message: "password must be at least 8 characters"}
+	}
+	if len(req.Password) > 72 { // bcrypt refuses anything longer
+		return &ValidationError{Field: "password", Message: "password must be at most 72 bytes"}
+	}
+	if req.Role != "" && !req.Role.Valid() {
+		return &ValidationError{Field: "role", Message: "invalid role"}
+	}
+	return nil
+}
+
+// validateUserUpdate checks the profile fields that were sent, nil fields are skipped
+func validateUserUpdate(req *models.UserUpdateRequest) error {
+	fields := []struct {
+		name  string
+		value *string
+		min   int
+		max   int
+	}{
+		{"name", req.Name, 1, 100},
+		{"email", req.Email, 1, 100},
+		{"username", req.Username, 3, 50},
+		{"bio", req.Bio, 0, 1000},
+		{"location", req.Location, 0, 100},
+		{"github_username", req.GithubUsername, 0, 100},
+		{"twitter_username", req.TwitterUsername, 0, 100},
+	}
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		length := len(strings.TrimSpace(*field.value))
+		if field.min > 0 && length == 0 {
+			return &ValidationError{Field: field.name, Message: field.name + " is required"}
+		}
+		if length < field.min {
+			return &ValidationError{Field: field.name, Message: fmt.Sprintf("%s must be at least %d characters", field.name, field.min)}
+		}
+		if len(*field.value) > field.max {
+			return &ValidationError{Field: field.name, Message: fmt.Sprintf("%s must be at most %d characters", field.name, field.max)}
+		}
+	}
+
+	if req.Email != nil {
+		// A bare address only, "Name <addr>" would be stored verbatim
+		if parsed, err := mail.ParseAddress(*req.Email); err != nil || parsed.Address != strings.TrimSpace(*req.Email) {
+			return &ValidationError{Field: "email", Message: "email must be a valid email address"}
+		}
+	}
+
+	urls := []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"website", req.Website, 200},
+		{"avatar", req.Avatar, 500},
+		{"linkedin_profile", req.LinkedinProfile, 200},
+	}
+	for _, field := range urls {
+		if field.value == nil || *field.value == "" {
+			continue
+		}
+		if len(*field.value) > field.max {
+			return &ValidationError{Field: field.name, Message: fmt.Sprintf("%s must be at most %d characters", field.name, field.max)}
+		}
+		parsed, err := url.ParseRequestURI(*field.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return &ValidationError{Field: field.name, Message: field.name + " must be an http(s) URL"}
+		}
+	}
+
+	return nil
+}
+
+// recentActivityLimit caps the activity entries on a user summary
+const recentActivityLimit = 10
+
+// UserSummaryResponse is the dashboard view of a user's own contributions
+type UserSummaryResponse struct {
+	models.AuthorSummary
+	RequestsHandled int64          `json:"requests_handled"`
+	RecentActivity  []ActivityItem `json:"recent_activity"`
+}
+
+// ActivityItem is one entry of a user's recent activity, newest first
+type ActivityItem struct {
+	Type      string `json:"type"` // prompt_created, prompt_verified or request_completed
+	PromptID  uint   `json:"prompt_id,omitempty"`
+	RequestID uint   `json:"request_id,omitempty"`
+	Title     string `json:"title"`
+	At        string `json:"at"`
+
+	at time.Time // sort key
+}
+
+// GetSummary aggregates the user's prompts, their engagement and handled requests
+// A user without any contributions gets zeroes and an empty activity list
+func (s *UserService) GetSummary(userID uint) (*UserSummaryResponse, error) {
+	summary, err := s.promptRepo.AuthorSummary(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize prompts: %w", err)
+	}
+
+	handled, err := s.requestRepo.CountCompletedBy(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count handled requests: %w", err)
+	}
+
+	prompts, err := s.promptRepo.FindRecentByAuthor(userID, recentActivityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent prompts: %w", err)
+	}
+
+	requests, err := s.requestRepo.FindCompletedBy(userID, recentActivityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent requests: %w", err)
+	}
+
+	activity := []ActivityItem{}
+	for _, prompt := range prompts {
+		activity = append(activity, ActivityItem{Type: "prompt_created", PromptID: prompt.ID, Title: prompt.Title, at: prompt.CreatedAt})
+		if prompt.IsVerified && prompt.VerifiedAt != nil {
+			activity = append(activity, ActivityItem{Type: "prompt_verified", PromptID: prompt.ID, Title: prompt.Title, at: *prompt.VerifiedAt})
+		}
+	}
+	for _, request := range requests {
+		completedAt := request.UpdatedAt
+		if request.CompletedAt != nil {
+			completedAt = time.Unix(*request.CompletedAt, 0)
+		}
+		activity = append(activity, ActivityItem{Type: "request_completed", RequestID: request.ID, Title: request.RequestedTitle, at: completedAt})
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool { return activity[i].at.After(activity[j].at) })
+	if len(activity) > recentActivityLimit {
+		activity = activity[:recentActivityLimit]
+	}
+	for i := range activity {
+		activity[i].At = formatTimestamp(activity[i].at)
+	}
+
+	return &UserSummaryResponse{
+		AuthorSummary:   summary,
+		RequestsHandled: handled,
+		RecentActivity:  activity,
+	}, nil
+}
+
+// GetProfileByUsername looks up a profile by username, shaped for the viewer
+// Inactive users are reported as not found unless the viewer is the user or an admin
+func (s *UserService) GetProfileByUsername(username string, viewer *models.User) (interface{}, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, errors.New("invalid username")
+	}
+
+	user, err := s.userRepo.FindByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return s.profileFor(user, viewer)
+}
+
+// profileFor shapes a user's profile for the viewer, with their authored difficulty breakdown
+// Inactive users are reported as not found unless the viewer is the user or an admin
+func (s *UserService) profileFor(user *models.User, viewer *models.User) (interface{}, error) {
+	if !user.IsActive && !user.CanViewPrivateDetails(viewer) {
+		return nil, errors.New("user not found")
+	}
+
+	breakdown, err := s.promptRepo.AuthorDifficultyBreakdown(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load difficulty breakdown: %w", err)
+	}
+
+	response := user.ResponseFor(viewer)
+	switch profile := response.(type) {
+	case *models.UserResponse:
+		profile.DifficultyBreakdown = breakdown
+	case *models.PublicUserResponse:
+		profile.DifficultyBreakdown = breakdown
+	}
+
+	return response, nil
+}
+
+// RecomputeStats rebuilds the denormalized user counters, e.g. after a bulk import
+// Returns how many users had a stale counter
+func (s *UserService) RecomputeStats() (int64, error) {
+	updated, err := s.userRepo.BackfillPromptsCreated()
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill prompts created: %w", err)
+	}
+	return updated, nil
+}
+
+// MergeUsers folds a duplicate account into the kept one and returns the kept user
+func (s *UserService) MergeUsers(req *models.UserMergeRequest) (*models.UserResponse, error) {
+	if req.Keep == 0 || req.Merge == 0 {
+		return nil, errors.New("keep and merge are required")
+	}
+	if req.Keep == req.Merge {
+		return nil, errors.New("invalid merge: keep and merge must be different users")
+	}
+
+	if err := s.userRepo.MergeInto(req.Keep, req.Merge); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	kept, err := s.userRepo.FindByID(req.Keep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return kept.ToResponse(), nil
+}
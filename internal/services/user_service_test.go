@@ -0,0 +1,267 @@
+package services
+
+import (
+	"PromptGallery/internal/config"
+	"PromptGallery/internal/models"
+	"PromptGallery/internal/testdb"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateUserCreate(t *testing.T) {
+	valid := func() models.UserCreateRequest {
+		return models.UserCreateRequest{Name: "Ann", Email: "ann@example.com", Username: "ann", Password: "correct horse"}
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(*models.UserCreateRequest)
+		wantField string
+	}{
+		{name: "valid", mutate: func(*models.UserCreateRequest) {}},
+		{name: "blank name", mutate: func(r *models.UserCreateRequest) { r.Name = "  " }, wantField: "name"},
+		{name: "display-name email", mutate: func(r *models.UserCreateRequest) { r.Email = "Ann <ann@example.com>" }, wantField: "email"},
+		{name: "not an email", mutate: func(r *models.UserCreateRequest) { r.Email = "ann" }, wantField: "email"},
+		{name: "short username", mutate: func(r *models.UserCreateRequest) { r.Username = "an" }, wantField: "username"},
+		{name: "long username", mutate: func(r *models.UserCreateRequest) { r.Username = strings.Repeat("a", 51) }, wantField: "username"},
+		{name: "long bio", mutate: func(r *models.UserCreateRequest) { r.Bio = strings.Repeat("a", 1001) }, wantField: "bio"},
+		{name: "ftp website", mutate: func(r *models.UserCreateRequest) { r.Website = "ftp://example.com" }, wantField: "website"},
+		{name: "https website", mutate: func(r *models.UserCreateRequest) { r.Website = "https://example.com/ann" }},
+		{name: "short password", mutate: func(r *models.UserCreateRequest) { r.Password = "1234567" }, wantField: "password"},
+		{name: "password past bcrypt limit", mutate: func(r *models.UserCreateRequest) { r.Password = strings.Repeat("a", 73) }, wantField: "password"},
+		{name: "unknown role", mutate: func(r *models.UserCreateRequest) { r.Role = "owner" }, wantField: "role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(&req)
+
+			err := validateUserCreate(&req)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("error = %v, want a validation error on %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateUserUpdateSkipsUnsentFields(t *testing.T) {
+	bio := strings.Repeat("a", 1000)
+	if err := validateUserUpdate(&models.UserUpdateRequest{Bio: &bio}); err != nil {
+		t.Errorf("bio at the limit rejected: %v", err)
+	}
+
+	empty := ""
+	if err := validateUserUpdate(&models.UserUpdateRequest{Website: &empty}); err != nil {
+		t.Errorf("clearing the website rejected: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if err := validateUserUpdate(&models.UserUpdateRequest{Name: &empty}); !errors.As(err, &validationErr) || validationErr.Field != "name" {
+		t.Errorf("clearing the name: error = %v, want a name validation error", err)
+	}
+}
+
+func TestCreateUserRoleNeedsAdmin(t *testing.T) {
+	s := &UserService{cfg: &config.Config{}}
+	req := &models.UserCreateRequest{Name: "Ann", Email: "ann@example.com", Username: "ann", Password: "correct horse", Role: models.RoleAdmin}
+
+	for _, creator := range []*models.User{nil, {Role: models.RoleModerator}} {
+		if _, err := s.CreateUser(req, creator); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("creator %v: error = %v, want role not allowed", creator, err)
+		}
+	}
+}
+
+func TestCheckEmailDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		email   string
+		wantErr bool
+	}{
+		{name: "empty config allows all", email: "ann@gmail.com"},
+		{name: "allowed domain", allowed: []string{"corp.com"}, email: "ann@corp.com"},
+		{name: "allowed domain, mixed case", allowed: []string{"corp.com"}, email: " Ann@Corp.COM "},
+		{name: "disallowed domain", allowed: []string{"corp.com"}, email: "ann@gmail.com", wantErr: true},
+		{name: "subdomain isn't the domain", allowed: []string{"corp.com"}, email: "ann@eu.corp.com", wantErr: true},
+		{name: "lookalike suffix", allowed: []string{"corp.com"}, email: "ann@evilcorp.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UserService{cfg: &config.Config{AllowedEmailDomains: tt.allowed}}
+
+			err := s.checkEmailDomain(tt.email)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("checkEmailDomain(%q) = %v, want error %v", tt.email, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "not allowed") {
+				t.Errorf("error %q doesn't map to 403", err)
+			}
+		})
+	}
+}
+
+func TestCreateUserRejectsDisallowedDomain(t *testing.T) {
+	s := &UserService{cfg: &config.Config{AllowedEmailDomains: []string{"corp.com"}}}
+	req := &models.UserCreateRequest{Name: "Ann", Email: "ann@gmail.com", Username: "ann", Password: "correct horse"}
+
+	if _, err := s.CreateUser(req, nil); err == nil || !strings.Contains(err.Error(), "email domain not allowed") {
+		t.Errorf("error = %v, want email domain not allowed", err)
+	}
+}
+
+func TestIssueToken(t *testing.T) {
+	cfg := testConfig()
+	cfg.LoginMaxFailures = 3
+	s := newTestServices(t, cfg)
+
+	ann := testdb.SeedUser(t, s.db, "ann", nil)
+	ann.SetPassword("correct horse", cfg.BcryptCost)
+	s.db.Save(ann)
+
+	inactive := testdb.SeedUser(t, s.db, "gone", nil)
+	inactive.SetPassword("correct horse", cfg.BcryptCost)
+	s.db.Save(inactive)
+	s.db.Model(inactive).Update("is_active", false)
+
+	failures := []models.TokenCreateRequest{
+		{Login: "ann", Password: "wrong"},
+		{Login: "nobody", Password: "correct horse"},
+		{Login: "gone@example.com", Password: "correct horse"},
+	}
+	for _, req := range failures {
+		if _, err := s.users.IssueToken(&req, "10.0.0.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("IssueToken(%s) error = %v, want ErrInvalidCredentials", req.Login, err)
+		}
+	}
+
+	// The right password doesn't help once the IP is throttled, another IP still signs in
+	right := models.TokenCreateRequest{Login: "ANN@example.com", Password: "correct horse", Name: "cli"}
+	if _, err := s.users.IssueToken(&right, "10.0.0.1"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("throttled IP error = %v, want ErrTooManyAttempts", err)
+	}
+	issued, err := s.users.IssueToken(&right, "10.0.0.2")
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	if !strings.HasPrefix(issued.Token, "pg_") || issued.Name != "cli" {
+		t.Errorf("token = %+v", issued)
+	}
+
+	var stored models.APIToken
+	s.db.Where("user_id = ?", ann.ID).First(&stored)
+	if stored.TokenHash == issued.Token || stored.TokenHash != models.HashToken(issued.Token) {
+		t.Error("token isn't stored as its hash")
+	}
+
+	user, err := s.users.UserForToken(issued.Token)
+	if err != nil || user.ID != ann.ID {
+		t.Fatalf("UserForToken = %v, %v, want ann", user, err)
+	}
+	if _, err := s.users.UserForToken("pg_unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown token error = %v, want ErrInvalidToken", err)
+	}
+
+	s.db.Model(&stored).Update("expires_at", time.Now().Add(-time.Minute))
+	if _, err := s.users.UserForToken(issued.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired token error = %v, want ErrInvalidToken", err)
+	}
+
+	second, _ := s.users.IssueToken(&right, "10.0.0.2")
+	if err := s.users.RevokeToken(second.Token); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := s.users.UserForToken(second.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoked token error = %v, want ErrInvalidToken", err)
+	}
+	if err := s.users.RevokeToken(second.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoking twice error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	s := newTestServices(t, testConfig())
+	author := testdb.SeedUser(t, s.db, "author", nil)
+	other := testdb.SeedUser(t, s.db, "other", nil)
+
+	now := time.Now()
+	verifiedAt := now.Add(-time.Hour)
+	seed := func(title string, views, likes int, verified bool) *models.Prompt {
+		prompt := testdb.SeedPrompt(t, s.db, func(p *models.Prompt) {
+			p.Title = title
+			p.AuthorID = &author.ID
+			if verified {
+				p.IsVerified, p.VerifiedBy, p.VerifiedAt = true, &other.ID, &verifiedAt
+			}
+		})
+		testdb.SetCounts(t, s.db, prompt.ID, views, likes)
+		return prompt
+	}
+	seed("Two Sum", 10, 2, true)
+	seed("Three Sum", 5, 1, true)
+	seed("Four Sum", 0, 0, false)
+	s.db.Delete(seed("Deleted", 100, 100, true))
+	testdb.SeedPrompt(t, s.db, func(p *models.Prompt) { p.AuthorID = &other.ID })
+
+	completedAt := now.Add(time.Minute).Unix()
+	for _, status := range []models.RequestStatus{models.StatusCompleted, models.StatusCompleted, models.StatusInProgress} {
+		testdb.SeedRequest(t, s.db, func(r *models.PromptRequest) {
+			r.Status = status
+			r.AssignedToID = &author.ID
+			if status == models.StatusCompleted {
+				r.CompletedAt = &completedAt
+			}
+		})
+	}
+
+	summary, err := s.users.GetSummary(author.ID)
+	if err != nil {
+		t.Fatalf("GetSummary: %v", err)
+	}
+
+	want := models.AuthorSummary{PromptCount: 3, VerifiedCount: 2, TotalViews: 15, TotalLikes: 3}
+	if summary.AuthorSummary != want || summary.RequestsHandled != 2 {
+		t.Errorf("summary = %+v, %d handled, want %+v, 2 handled", summary.AuthorSummary, summary.RequestsHandled, want)
+	}
+
+	// 3 created, 2 verified and 2 completed requests
+	if len(summary.RecentActivity) != 7 {
+		t.Fatalf("%d activity items, want 7: %+v", len(summary.RecentActivity), summary.RecentActivity)
+	}
+	for i := 1; i < len(summary.RecentActivity); i++ {
+		if summary.RecentActivity[i].At > summary.RecentActivity[i-1].At {
+			t.Errorf("activity not newest first: %+v", summary.RecentActivity)
+			break
+		}
+	}
+	if first := summary.RecentActivity[0]; first.Type != "request_completed" {
+		t.Errorf("newest activity = %+v, want a completed request", first)
+	}
+
+	t.Run("new user", func(t *testing.T) {
+		newcomer := testdb.SeedUser(t, s.db, "newcomer", nil)
+
+		summary, err := s.users.GetSummary(newcomer.ID)
+		if err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if summary.AuthorSummary != (models.AuthorSummary{}) || summary.RequestsHandled != 0 {
+			t.Errorf("summary = %+v, %d handled, want zeroes", summary.AuthorSummary, summary.RequestsHandled)
+		}
+		if summary.RecentActivity == nil || len(summary.RecentActivity) != 0 {
+			t.Errorf("recent activity = %#v, want an empty list", summary.RecentActivity)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"log"
+	"sync"
+)
+
+// Pool runs fire-and-forget tasks on a fixed number of goroutines
+// The queue is bounded, so a burst can't spawn unbounded goroutines: when it's full
+// Submit drops the task instead of blocking the caller
+type Pool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewPool(workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &Pool{
+		tasks: make(chan func(), queueSize),
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		run(task)
+	}
+}
+
+// Submit queues task and reports whether it was accepted
+// A nil Pool runs the task synchronously, handy for one-off commands and tools
+func (p *Pool) Submit(task func()) bool {
+	if p == nil {
+		run(task)
+		return true
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop rejects new tasks and waits until everything already queued has run
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.tasks)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func run(task func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Background task panicked: %v", r)
+		}
+	}()
+	task()
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestPoolRunsSubmittedTasks(t *testing.T) {
+	p := NewPool(4, 100)
+
+	var ran atomic.Int32
+	for i := 0; i < 50; i++ {
+		if !p.Submit(func() { ran.Add(1) }) {
+			t.Fatalf("task %d rejected with room in the queue", i)
+		}
+	}
+	p.Stop()
+
+	if got := ran.Load(); got != 50 {
+		t.Errorf("ran %d tasks, want 50", got)
+	}
+}
+
+func TestPoolDropsWhenFull(t *testing.T) {
+	p := NewPool(1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started // the worker is busy, the queue is empty
+
+	if !p.Submit(func() {}) {
+		t.Fatal("queued task rejected with one free slot")
+	}
+	if p.Submit(func() {}) {
+		t.Error("task accepted with the queue full, want it dropped")
+	}
+
+	close(release)
+	p.Stop()
+}
+
+func TestPoolStopDrainsQueue(t *testing.T) {
+	p := NewPool(1, 10)
+
+	release := make(chan struct{})
+	var ran atomic.Int32
+	p.Submit(func() { <-release })
+	for i := 0; i < 5; i++ {
+		p.Submit(func() { ran.Add(1) })
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopped)
+	}()
+
+	close(release)
+	<-stopped
+
+	if got := ran.Load(); got != 5 {
+		t.Errorf("Stop returned after %d of 5 queued tasks", got)
+	}
+	if p.Submit(func() {}) {
+		t.Error("task accepted after Stop")
+	}
+	p.Stop() // a second Stop is a no-op
+}
+
+func TestPoolSurvivesPanics(t *testing.T) {
+	p := NewPool(1, 10)
+
+	var ran atomic.Bool
+	p.Submit(func() { panic("boom") })
+	p.Submit(func() { ran.Store(true) })
+	p.Stop()
+
+	if !ran.Load() {
+		t.Error("worker died with the panicking task")
+	}
+}
+
+func TestNilPoolRunsInline(t *testing.T) {
+	var p *Pool
+
+	ran := false
+	if !p.Submit(func() { ran = true }) || !ran {
+		t.Error("nil pool didn't run the task synchronously")
+	}
+}